@@ -0,0 +1,234 @@
+package scout
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// DefaultAlertInterval is the interval watchers are evaluated over unless set with WithAlertInterval.
+const DefaultAlertInterval = time.Minute
+
+// maxWatchedDurations bounds the span durations kept per latency watcher and interval,
+// beyond which they are sampled.
+const maxWatchedDurations = 10000
+
+// Alert is passed to the callback of a watcher when it trips or recovers.
+type Alert struct {
+	// Name is the name of the watcher.
+	Name string
+	// Firing is true when the watcher trips, and false when it recovers.
+	Firing bool
+	// Value is the value observed over the last interval: errors per minute for error rate watchers,
+	// and the latency quantile in seconds for latency watchers.
+	Value float64
+	// Threshold is the value the watcher trips above.
+	Threshold float64
+}
+
+type alertWatcher struct {
+	name      string
+	threshold float64
+	callback  func(Alert)
+	// pattern matches the names of the spans observed, or every span when nil
+	pattern *regexp.Regexp
+	// quantile is the latency quantile watched, or zero for the error rate
+	quantile float64
+}
+
+// WithErrorRateWatcher calls callback when more than perMinute errors are recorded per minute, and again
+// once the rate falls back under it, so a service can shed load or open a circuit breaker from the same
+// telemetry it exports. Watchers are evaluated every DefaultAlertInterval, see WithAlertInterval. They
+// observe the spans that are sampled, and scale their errors up by the sampling rate of their span kind,
+// so the rate is of every error recorded. A watcher without a callback is ignored.
+func WithErrorRateWatcher(name string, perMinute float64, callback func(Alert)) Option {
+	return option(func(conf *config) {
+		if callback == nil {
+			logger.Warnf("ignoring scout watcher %s without a callback", name)
+			return
+		}
+		conf.alertWatchers = append(conf.alertWatchers, alertWatcher{name: name, threshold: perMinute, callback: callback})
+	})
+}
+
+// WithLatencyWatcher calls callback when the quantile, such as 0.99, of the duration of the spans whose name
+// matches pattern exceeds threshold, and again once it falls back under it. For example:
+//
+//	scout.WithLatencyWatcher("checkout-p99", regexp.MustCompile(`^POST /checkout$`), 0.99, time.Second, func(alert scout.Alert) {
+//		breaker.SetOpen(alert.Firing)
+//	})
+//
+// Intervals without a matching span leave the watcher as it is. A watcher without a callback is ignored.
+func WithLatencyWatcher(name string, pattern *regexp.Regexp, quantile float64, threshold time.Duration, callback func(Alert)) Option {
+	return option(func(conf *config) {
+		if callback == nil {
+			logger.Warnf("ignoring scout watcher %s without a callback", name)
+			return
+		}
+		conf.alertWatchers = append(conf.alertWatchers, alertWatcher{
+			name:      name,
+			threshold: threshold.Seconds(),
+			callback:  callback,
+			pattern:   pattern,
+			quantile:  quantile,
+		})
+	})
+}
+
+// WithAlertInterval sets the interval the watchers set by WithErrorRateWatcher and WithLatencyWatcher
+// are evaluated over. Shorter intervals react faster, at the cost of noisier values.
+func WithAlertInterval(interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.alertInterval = interval
+	})
+}
+
+type watcherState struct {
+	alertWatcher
+	errors    float64
+	durations []time.Duration
+	// observed counts the durations seen in the interval, to sample them once maxWatchedDurations are kept
+	observed int
+	firing   bool
+}
+
+// alertProcessor observes ended spans for the watchers and evaluates them every interval.
+type alertProcessor struct {
+	interval time.Duration
+	// sampler is the sampler of the spans observed, to scale up their errors, or nil if the spans are
+	// sampled by the application's tracer provider
+	sampler *sampler
+
+	mu       sync.Mutex
+	watchers []*watcherState
+}
+
+var _ sdktrace.SpanProcessor = (*alertProcessor)(nil)
+
+func newAlertProcessor(watchers []alertWatcher, interval time.Duration, sampler *sampler) *alertProcessor {
+	p := &alertProcessor{interval: interval, sampler: sampler}
+	for _, w := range watchers {
+		p.watchers = append(p.watchers, &watcherState{alertWatcher: w})
+	}
+	return p
+}
+
+func (p *alertProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *alertProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	weight := 1.
+	if p.sampler != nil {
+		weight = p.sampler.weight(s)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, w := range p.watchers {
+		if w.pattern != nil && !w.pattern.MatchString(s.Name()) {
+			continue
+		}
+		if w.quantile == 0 {
+			w.errors += spanErrors(s) * weight
+			continue
+		}
+		duration := s.EndTime().Sub(s.StartTime())
+		w.observed++
+		if len(w.durations) < maxWatchedDurations {
+			w.durations = append(w.durations, duration)
+		} else if i := rand.Intn(w.observed); i < maxWatchedDurations {
+			w.durations[i] = duration
+		}
+	}
+}
+
+func (p *alertProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p *alertProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// spanErrors returns the number of errors recorded on s, counting a span ended with an error status
+// without recording an error as one. Cancellations are not failures of the service, so they are not counted.
+func spanErrors(s sdktrace.ReadOnlySpan) float64 {
+	var errors float64
+	var exceptions int
+	for _, event := range s.Events() {
+		if event.Name != semconv.ExceptionEventName {
+			continue
+		}
+		exceptions++
+		count, canceled := 1., false
+		for _, kv := range event.Attributes {
+			switch kv.Key {
+			case ErrorCountAttribute:
+				count = float64(kv.Value.AsInt64())
+			case ErrorCancellationAttribute:
+				canceled = true
+			}
+		}
+		if !canceled {
+			errors += count
+		}
+	}
+	if exceptions == 0 && s.Status().Code == codes.Error {
+		return 1
+	}
+	return errors
+}
+
+// quantile returns the q quantile of durations, in seconds.
+func quantile(durations []time.Duration, q float64) float64 {
+	slices.Sort(durations)
+	i := int(math.Ceil(q*float64(len(durations)))) - 1
+	return durations[max(min(i, len(durations)-1), 0)].Seconds()
+}
+
+// evaluate compares the values observed since the last call to the thresholds of the watchers,
+// and calls the callbacks of the watchers that tripped or recovered.
+func (p *alertProcessor) evaluate(context.Context) {
+	var alerts []Alert
+	var callbacks []func(Alert)
+	p.mu.Lock()
+	for _, w := range p.watchers {
+		var value float64
+		if w.quantile == 0 {
+			value = w.errors / p.interval.Minutes()
+		} else if len(w.durations) > 0 {
+			value = quantile(w.durations, w.quantile)
+		} else {
+			continue
+		}
+		w.errors, w.durations, w.observed = 0, nil, 0
+		if firing := value > w.threshold; firing != w.firing {
+			w.firing = firing
+			alerts = append(alerts, Alert{Name: w.name, Firing: firing, Value: value, Threshold: w.threshold})
+			callbacks = append(callbacks, w.callback)
+		}
+	}
+	p.mu.Unlock()
+	// callbacks run outside the lock, so they may record spans
+	for i, alert := range alerts {
+		state := "recovered"
+		if alert.Firing {
+			state = "tripped"
+		}
+		logger.Infof("scout watcher %s %s at %g, with a threshold of %g", alert.Name, state, alert.Value, alert.Threshold)
+		callbacks[i](alert)
+	}
+}
+
+func startAlerts(o *OTLP) {
+	if o == nil || o.alerts == nil {
+		return
+	}
+	startPeriodic(o.alerts.interval, o.alerts.evaluate)
+}
@@ -0,0 +1,106 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestErrorRateWatcher(t *testing.T) {
+	var alerts []Alert
+	watch := func(alert Alert) { alerts = append(alerts, alert) }
+	p := newAlertProcessor([]alertWatcher{{name: "errors", threshold: 2, callback: watch}}, 30*time.Second, nil)
+
+	exception := sdktrace.Event{Name: semconv.ExceptionEventName}
+	p.OnEnd(tracetest.SpanStub{Events: []sdktrace.Event{exception}}.Snapshot())
+	p.OnEnd(tracetest.SpanStub{Status: sdktrace.Status{Code: codes.Error}}.Snapshot())
+	p.evaluate(context.Background())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, Alert{Name: "errors", Firing: true, Value: 4, Threshold: 2}, alerts[0])
+
+	// unchanged watchers are not called again
+	p.OnEnd(tracetest.SpanStub{Events: []sdktrace.Event{{
+		Name:       semconv.ExceptionEventName,
+		Attributes: []attribute.KeyValue{attribute.Int(ErrorCountAttribute, 3)},
+	}}}.Snapshot())
+	p.evaluate(context.Background())
+	require.Len(t, alerts, 1)
+
+	p.evaluate(context.Background())
+	require.Len(t, alerts, 2)
+	assert.False(t, alerts[1].Firing)
+	assert.Zero(t, alerts[1].Value)
+}
+
+func TestErrorRateWatcherScalesSampledErrors(t *testing.T) {
+	var alerts []Alert
+	s := sampler{traceIDUpperBounds: map[trace.SpanKind]uint64{trace.SpanKindUnspecified: 1 << 63, trace.SpanKindServer: 1 << 61}}
+	p := newAlertProcessor([]alertWatcher{{name: "errors", threshold: 1, callback: func(alert Alert) { alerts = append(alerts, alert) }}}, time.Minute, &s)
+
+	sampled := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	failed := sdktrace.Status{Code: codes.Error}
+	// a server span sampled at a rate of 0.25 stands for 4
+	p.OnEnd(tracetest.SpanStub{SpanContext: sampled, SpanKind: trace.SpanKindServer, Status: failed}.Snapshot())
+	p.OnEnd(tracetest.SpanStub{
+		SpanContext: sampled,
+		SpanKind:    trace.SpanKindServer,
+		Status:      failed,
+		Attributes:  []attribute.KeyValue{attribute.Bool(ForceSampleAttribute, true)},
+	}.Snapshot())
+	p.OnEnd(tracetest.SpanStub{SpanContext: sampled, Status: failed}.Snapshot())
+	// recorded without being sampled, so already stood for by the sampled spans
+	p.OnEnd(tracetest.SpanStub{SpanKind: trace.SpanKindServer, Status: failed}.Snapshot())
+	p.evaluate(context.Background())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 6., alerts[0].Value)
+}
+
+func TestWatcherWithoutCallback(t *testing.T) {
+	c := &config{}
+	WithErrorRateWatcher("errors", 1, nil).apply(c)
+	WithLatencyWatcher("latency", nil, 0.99, time.Second, nil).apply(c)
+	assert.Empty(t, c.alertWatchers)
+}
+
+func TestLatencyWatcher(t *testing.T) {
+	var alerts []Alert
+	p := newAlertProcessor([]alertWatcher{{
+		name:      "checkout",
+		threshold: 0.5,
+		callback:  func(alert Alert) { alerts = append(alerts, alert) },
+		pattern:   regexp.MustCompile(`^checkout$`),
+		quantile:  0.9,
+	}}, time.Minute, nil)
+
+	start := time.Now()
+	for i := 1; i <= 10; i++ {
+		p.OnEnd(tracetest.SpanStub{Name: "checkout", StartTime: start, EndTime: start.Add(time.Duration(i) * 100 * time.Millisecond)}.Snapshot())
+	}
+	p.OnEnd(tracetest.SpanStub{Name: "search", StartTime: start, EndTime: start.Add(time.Minute)}.Snapshot())
+	p.evaluate(context.Background())
+	require.Len(t, alerts, 1)
+	assert.True(t, alerts[0].Firing)
+	assert.InDelta(t, 0.9, alerts[0].Value, 1e-9)
+
+	// intervals without requests leave the watcher firing
+	p.evaluate(context.Background())
+	assert.Len(t, alerts, 1)
+}
+
+func TestSpanErrors(t *testing.T) {
+	recorder := recordSpans(t)
+	RecordErrors(context.Background(), []error{errors.New("a"), errors.New("a"), errors.New("b")})
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, 3., spanErrors(recorder.Ended()[0]))
+}
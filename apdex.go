@@ -0,0 +1,144 @@
+package scout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	ApdexMetric           = "http.apdex"
+	ApdexSatisfiedMetric  = "http.apdex.satisfied"
+	ApdexToleratingMetric = "http.apdex.tolerating"
+	ApdexFrustratedMetric = "http.apdex.frustrated"
+	// ApdexThresholdAttribute holds the threshold of the scores, in seconds
+	ApdexThresholdAttribute = "http.apdex.threshold"
+)
+
+// apdexOtherRoute collects requests to routes beyond maxApdexRoutes.
+const apdexOtherRoute = "other"
+
+// maxApdexRoutes bounds the number of routes tracked per interval.
+const maxApdexRoutes = 1000
+
+// WithApdex computes the Apdex score of each HTTP route from the spans of the Scout middleware,
+// given a target response time threshold, and records it every interval. Requests are satisfied
+// within threshold, tolerating within four times threshold, and frustrated beyond that or when
+// they fail with a 5xx status or an error. Scores are computed from sampled requests.
+func WithApdex(threshold, interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.apdexThreshold = threshold
+		conf.apdexPeriod = interval
+	})
+}
+
+type apdexCounts struct {
+	satisfied, tolerating, frustrated int64
+}
+
+func (c apdexCounts) score() float64 {
+	total := c.satisfied + c.tolerating + c.frustrated
+	return (float64(c.satisfied) + float64(c.tolerating)/2) / float64(total)
+}
+
+// apdexProcessor counts the requests of each route by Apdex zone.
+type apdexProcessor struct {
+	threshold time.Duration
+
+	mu     sync.Mutex
+	routes map[string]*apdexCounts
+}
+
+var _ sdktrace.SpanProcessor = (*apdexProcessor)(nil)
+
+func newApdexProcessor(threshold time.Duration) *apdexProcessor {
+	return &apdexProcessor{threshold: threshold, routes: map[string]*apdexCounts{}}
+}
+
+func (p *apdexProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *apdexProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	var route string
+	var status int
+	for _, kv := range s.Attributes() {
+		switch kv.Key {
+		case semconv.HTTPRouteKey:
+			route = kv.Value.AsString()
+		case semconv.HTTPStatusCodeKey:
+			status = int(kv.Value.AsInt64())
+		}
+	}
+	if route == "" {
+		return
+	}
+	duration := s.EndTime().Sub(s.StartTime())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	counts, ok := p.routes[route]
+	if !ok {
+		if len(p.routes) >= maxApdexRoutes {
+			route = apdexOtherRoute
+			counts = p.routes[route]
+		}
+		if counts == nil {
+			counts = &apdexCounts{}
+			p.routes[route] = counts
+		}
+	}
+	switch {
+	case status >= 500 || s.Status().Code == codes.Error || duration > 4*p.threshold:
+		counts.frustrated++
+	case duration > p.threshold:
+		counts.tolerating++
+	default:
+		counts.satisfied++
+	}
+}
+
+func (p *apdexProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p *apdexProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// record records the scores of the routes requested since the last call and resets the counts.
+func (p *apdexProcessor) record(ctx context.Context) {
+	p.mu.Lock()
+	routes := p.routes
+	p.routes = map[string]*apdexCounts{}
+	p.mu.Unlock()
+	if len(routes) == 0 {
+		return
+	}
+
+	span, _ := startInternalTrace(ctx, ScopedKey("apdex", ptr.String("-")), Duration(ApdexThresholdAttribute, p.threshold))
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return
+	}
+	for route, counts := range routes {
+		attr := attribute.String(string(semconv.HTTPRouteKey), route)
+		addGaugeEvent(span, ApdexMetric, counts.score(), attr)
+		addGaugeEvent(span, ApdexSatisfiedMetric, float64(counts.satisfied), attr)
+		addGaugeEvent(span, ApdexToleratingMetric, float64(counts.tolerating), attr)
+		addGaugeEvent(span, ApdexFrustratedMetric, float64(counts.frustrated), attr)
+	}
+}
+
+func startApdex(o *OTLP) {
+	conf := loadConfig()
+	if o == nil || o.apdex == nil {
+		return
+	}
+	onStopWorkers(func() { o.apdex.record(context.Background()) })
+	startPeriodic(conf.apdexPeriod, o.apdex.record)
+}
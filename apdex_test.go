@@ -0,0 +1,48 @@
+package scout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func requestSpan(route string, duration time.Duration, status int) sdktrace.ReadOnlySpan {
+	start := time.Now()
+	return tracetest.SpanStub{
+		StartTime: start,
+		EndTime:   start.Add(duration),
+		Attributes: []attribute.KeyValue{
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPStatusCodeKey.Int(status),
+		},
+	}.Snapshot()
+}
+
+func TestApdex(t *testing.T) {
+	recorder := recordSpans(t)
+	p := newApdexProcessor(100 * time.Millisecond)
+
+	p.OnEnd(requestSpan("/users", 50*time.Millisecond, 200))
+	p.OnEnd(requestSpan("/users", 50*time.Millisecond, 200))
+	p.OnEnd(requestSpan("/users", 300*time.Millisecond, 200))
+	p.OnEnd(requestSpan("/users", 50*time.Millisecond, 500))
+	p.OnEnd(requestSpan("/orders", time.Second, 200))
+	p.OnEnd(tracetest.SpanStub{Name: "background"}.Snapshot())
+
+	assert.Equal(t, apdexCounts{satisfied: 2, tolerating: 1, frustrated: 1}, *p.routes["/users"])
+	assert.Equal(t, 0.625, p.routes["/users"].score())
+	assert.Equal(t, apdexCounts{frustrated: 1}, *p.routes["/orders"])
+
+	p.record(context.Background())
+	assert.Empty(t, p.routes)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Len(t, spans[0].Events(), 8)
+}
@@ -0,0 +1,132 @@
+package scout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorAttribute is the key used by Err.
+const ErrorAttribute = "error"
+
+// String returns a string attribute.
+func String(key, value string) attribute.KeyValue {
+	return attribute.String(key, value)
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) attribute.KeyValue {
+	return attribute.Int(key, value)
+}
+
+// Float returns a floating point attribute.
+func Float(key string, value float64) attribute.KeyValue {
+	return attribute.Float64(key, value)
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) attribute.KeyValue {
+	return attribute.Bool(key, value)
+}
+
+// Duration returns an attribute holding the duration in seconds, matching metric.Duration.
+func Duration(key string, value time.Duration) attribute.KeyValue {
+	return attribute.Float64(key, value.Seconds())
+}
+
+// Err returns an attribute holding the error message, or an empty string for a nil error.
+func Err(err error) attribute.KeyValue {
+	if err == nil {
+		return attribute.String(ErrorAttribute, "")
+	}
+	return attribute.String(ErrorAttribute, err.Error())
+}
+
+// Attrs converts a map to attributes sorted by key. Values of unsupported types are formatted with %+v.
+//
+// For example:
+//
+//	scout.RecordError(ctx, err, scout.Attrs(map[string]any{"user.id": id, "retry": true})...)
+func Attrs(m map[string]any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(m))
+	for k, v := range m {
+		attrs = append(attrs, anyAttribute(k, v))
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+	return attrs
+}
+
+func anyAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprintf("%+v", v))
+	}
+}
+
+// requestAttributesKey holds the attributes accumulated by AddRequestAttributes.
+const requestAttributesKey = Scout + "RequestAttributes"
+
+type requestAttributes struct {
+	mu    sync.Mutex
+	attrs []attribute.KeyValue
+}
+
+// ContextWithRequestAttributes returns a copy of ctx accumulating the attributes added with
+// AddRequestAttributes during a request. Middleware set them on the request's span once it completes.
+func ContextWithRequestAttributes(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestAttributesKey, &requestAttributes{})
+}
+
+// AddRequestAttributes adds attributes to the span of the request handled in ctx, such as the
+// authenticated user or tenant, without access to the span. Outside of a request traced by middleware,
+// the attributes are set on the active span instead.
+func AddRequestAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	acc, ok := ctx.Value(requestAttributesKey).(*requestAttributes)
+	if !ok {
+		trace.SpanFromContext(ctx).SetAttributes(attrs...)
+		return
+	}
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	acc.attrs = append(acc.attrs, attrs...)
+}
+
+// RequestAttributes returns the attributes added with AddRequestAttributes during the request handled in ctx.
+func RequestAttributes(ctx context.Context) []attribute.KeyValue {
+	acc, ok := ctx.Value(requestAttributesKey).(*requestAttributes)
+	if !ok {
+		return nil
+	}
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return append([]attribute.KeyValue{}, acc.attrs...)
+}
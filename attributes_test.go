@@ -0,0 +1,52 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAttrs(t *testing.T) {
+	attrs := Attrs(map[string]any{
+		"b.int":      1,
+		"a.string":   "x",
+		"c.duration": 1500 * time.Millisecond,
+		"d.error":    errors.New("failed"),
+		"e.struct":   struct{ ID int }{ID: 1},
+	})
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("a.string", "x"),
+		attribute.Int("b.int", 1),
+		attribute.Float64("c.duration", 1.5),
+		attribute.String("d.error", "failed"),
+		attribute.String("e.struct", "{ID:1}"),
+	}, attrs)
+}
+
+func TestAddRequestAttributes(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx := ContextWithRequestAttributes(context.Background())
+	span, ctx := StartTrace(ctx, "request")
+	child, childCtx := StartTrace(ctx, "handler")
+	AddRequestAttributes(childCtx, attribute.String("user.id", "1"))
+	AddRequestAttributes(childCtx, attribute.Bool("cache.hit", true))
+	EndTrace(child)
+	span.SetAttributes(RequestAttributes(ctx)...)
+	EndTrace(span)
+
+	spans := recorder.Ended()
+	assert.NotContains(t, spans[0].Attributes(), attribute.String("user.id", "1"))
+	assert.Contains(t, spans[1].Attributes(), attribute.String("user.id", "1"))
+	assert.Contains(t, spans[1].Attributes(), attribute.Bool("cache.hit", true))
+
+	span, ctx = StartTrace(context.Background(), "no middleware")
+	AddRequestAttributes(ctx, attribute.String("user.id", "2"))
+	EndTrace(span)
+	assert.Contains(t, recorder.Ended()[2].Attributes(), attribute.String("user.id", "2"))
+	assert.Empty(t, RequestAttributes(ctx))
+}
@@ -0,0 +1,353 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Signal identifies the kind of telemetry carried by an exported span.
+type Signal string
+
+const (
+	SignalTrace  Signal = "trace"
+	SignalLog    Signal = "log"
+	SignalMetric Signal = "metric"
+)
+
+// DropPolicy decides which telemetry is discarded once the memory limit is reached.
+type DropPolicy byte
+
+const (
+	// DropNew discards incoming telemetry unless lower priority telemetry can be evicted to make room.
+	DropNew DropPolicy = iota
+	// DropOldest evicts the oldest buffered telemetry of equal or lower priority to make room.
+	DropOldest
+)
+
+// spanOverheadBytes approximates the fixed cost of a buffered span (ids, timestamps, resource pointers).
+const spanOverheadBytes = 256
+
+var defaultSignalPriority = []Signal{SignalTrace, SignalLog, SignalMetric}
+
+var droppedTelemetry = map[Signal]*atomic.Int64{
+	SignalTrace:  {},
+	SignalLog:    {},
+	SignalMetric: {},
+}
+
+// WithMemoryLimit caps the memory used by telemetry buffered for export.
+// Once the cap is reached, telemetry is dropped according to the policy.
+func WithMemoryLimit(bytes int64, policy DropPolicy) Option {
+	return option(func(conf *config) {
+		conf.memoryLimit = bytes
+		conf.dropPolicy = policy
+	})
+}
+
+// WithSignalPriority orders signals from most to least important.
+// Lower priority signals are dropped first when the memory limit is reached.
+func WithSignalPriority(signals ...Signal) Option {
+	return option(func(conf *config) {
+		conf.signalPriority = signals
+	})
+}
+
+// DroppedTelemetry returns the number of spans and logs dropped per signal because of the memory limit or,
+// without a memory limit, because the queue of logs waiting to be exported was full.
+func DroppedTelemetry() map[Signal]int64 {
+	counts := make(map[Signal]int64, len(droppedTelemetry))
+	for signal, count := range droppedTelemetry {
+		counts[signal] = count.Load()
+	}
+	return counts
+}
+
+func recordDrop(signal Signal) {
+	droppedTelemetry[signal].Add(1)
+}
+
+// signalOf infers the signal of a span from the events scout attaches to it.
+func signalOf(s sdktrace.ReadOnlySpan) Signal {
+	for _, event := range s.Events() {
+		switch event.Name {
+		case LogEvent:
+			return SignalLog
+		case MetricEvent:
+			return SignalMetric
+		}
+	}
+	return SignalTrace
+}
+
+func estimateSpanSize(s sdktrace.ReadOnlySpan) int64 {
+	size := int64(spanOverheadBytes + len(s.Name()))
+	for _, attr := range s.Attributes() {
+		size += int64(len(attr.Key) + len(attr.Value.Emit()))
+	}
+	for _, event := range s.Events() {
+		size += int64(len(event.Name))
+		for _, attr := range event.Attributes {
+			size += int64(len(attr.Key) + len(attr.Value.Emit()))
+		}
+	}
+	return size
+}
+
+// memoryBudget bounds the memory held by the telemetry queued for export, shared by the budgetProcessor
+// batching spans and the exporter of log records, so the limit caps the memory of both.
+type memoryBudget struct {
+	limit    int64
+	policy   DropPolicy
+	priority map[Signal]int
+	// signals lists the signals from least to most important
+	signals []Signal
+
+	mu     sync.Mutex
+	size   int64
+	queues []budgetQueue
+}
+
+// budgetQueue is a queue of telemetry bounded by a memoryBudget.
+type budgetQueue interface {
+	// evictOldest removes the oldest queued telemetry of signal, returning its size, or false if none is queued.
+	evictOldest(signal Signal) (int64, bool)
+}
+
+func newMemoryBudget(limit int64, policy DropPolicy, signals []Signal) *memoryBudget {
+	if len(signals) == 0 {
+		signals = defaultSignalPriority
+	}
+	priority := make(map[Signal]int, len(signals))
+	for i, signal := range signals {
+		priority[signal] = len(signals) - i
+	}
+	ordered := slices.Clone(defaultSignalPriority)
+	slices.SortStableFunc(ordered, func(a, b Signal) int {
+		return priority[a] - priority[b]
+	})
+	return &memoryBudget{limit: limit, policy: policy, priority: priority, signals: ordered}
+}
+
+// register bounds q by the budget, so its telemetry can be evicted to make room.
+func (b *memoryBudget) register(q budgetQueue) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queues = append(b.queues, q)
+}
+
+// reserve makes room for telemetry of signal and size, evicting queued telemetry of lower priority, or of
+// equal priority with DropOldest. It returns false, recording the drop, if there is no room for it.
+// The size must be released once the telemetry leaves its queue other than by eviction.
+func (b *memoryBudget) reserve(signal Signal, size int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.size+size > b.limit {
+		evicted, ok := b.evict(signal)
+		if !ok {
+			recordDrop(signal)
+			return false
+		}
+		b.size -= evicted
+	}
+	b.size += size
+	return true
+}
+
+// evict removes the oldest queued telemetry of the lowest priority that may make room for telemetry of
+// the incoming signal, returning its size, or false if there is none.
+func (b *memoryBudget) evict(incoming Signal) (int64, bool) {
+	limit := b.priority[incoming]
+	if b.policy == DropNew {
+		// only strictly lower priority telemetry may be evicted
+		limit--
+	}
+	for _, signal := range b.signals {
+		if b.priority[signal] > limit {
+			break
+		}
+		for _, q := range b.queues {
+			if size, ok := q.evictOldest(signal); ok {
+				recordDrop(signal)
+				return size, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// release returns the size of telemetry leaving its queue to the budget.
+func (b *memoryBudget) release(size int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.size -= size
+}
+
+type budgetedSpan struct {
+	span   sdktrace.ReadOnlySpan
+	signal Signal
+	size   int64
+}
+
+// budgetProcessor is a batching span processor that bounds the memory held by queued spans.
+type budgetProcessor struct {
+	exporter     sdktrace.SpanExporter
+	budget       *memoryBudget
+	batchTimeout time.Duration
+	maxBatchSize int
+
+	mu    sync.Mutex
+	queue []budgetedSpan
+
+	exportMu sync.Mutex
+	flushCh  chan struct{}
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+var _ sdktrace.SpanProcessor = (*budgetProcessor)(nil)
+
+func newBudgetProcessor(exporter sdktrace.SpanExporter, budget *memoryBudget, batchTimeout time.Duration, maxBatchSize int) *budgetProcessor {
+	p := &budgetProcessor{
+		exporter:     exporter,
+		budget:       budget,
+		batchTimeout: batchTimeout,
+		maxBatchSize: maxBatchSize,
+		flushCh:      make(chan struct{}, 1),
+		stopCh:       make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	budget.register(p)
+	go p.run()
+	return p
+}
+
+func (p *budgetProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *budgetProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	item := budgetedSpan{span: s, signal: signalOf(s), size: estimateSpanSize(s)}
+	if !p.budget.reserve(item.signal, item.size) {
+		return
+	}
+
+	p.mu.Lock()
+	p.queue = append(p.queue, item)
+	full := len(p.queue) >= p.maxBatchSize
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *budgetProcessor) evictOldest(signal Signal) (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, item := range p.queue {
+		if item.signal == signal {
+			p.queue = slices.Delete(p.queue, i, i+1)
+			return item.size, true
+		}
+	}
+	return 0, false
+}
+
+func (p *budgetProcessor) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.batchTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+		case <-p.flushCh:
+		}
+		if err := p.export(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// export sends all queued spans to the exporter in batches.
+func (p *budgetProcessor) export(ctx context.Context) error {
+	p.exportMu.Lock()
+	defer p.exportMu.Unlock()
+	for {
+		p.mu.Lock()
+		n := len(p.queue)
+		if n > p.maxBatchSize {
+			n = p.maxBatchSize
+		}
+		batch := make([]sdktrace.ReadOnlySpan, n)
+		var size int64
+		for i, item := range p.queue[:n] {
+			batch[i] = item.span
+			size += item.size
+		}
+		p.queue = p.queue[n:]
+		p.mu.Unlock()
+		p.budget.release(size)
+
+		if n == 0 {
+			return nil
+		}
+		if err := p.exporter.ExportSpans(ctx, batch); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *budgetProcessor) ForceFlush(ctx context.Context) error {
+	return p.export(ctx)
+}
+
+func (p *budgetProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+		<-p.done
+		if err = p.export(ctx); err != nil {
+			return
+		}
+		err = p.exporter.Shutdown(ctx)
+	})
+	return err
+}
+
+// multiExporter exports spans to each of its exporters, so they share the queue of a budgetProcessor.
+type multiExporter []sdktrace.SpanExporter
+
+var _ sdktrace.SpanExporter = multiExporter{}
+
+func (m multiExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var errs []error
+	for _, exporter := range m {
+		if err := exporter.ExportSpans(ctx, spans); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range m {
+		if err := exporter.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+package scout
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *recordingExporter) names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var names []string
+	for _, s := range e.spans {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func stubSpan(name string, event string) sdktrace.ReadOnlySpan {
+	stub := tracetest.SpanStub{
+		Name: name,
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{1},
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+	if event != "" {
+		stub.Events = []sdktrace.Event{{Name: event}}
+	}
+	return stub.Snapshot()
+}
+
+func TestBudgetProcessor(t *testing.T) {
+	size := estimateSpanSize(stubSpan("a", LogEvent))
+	tests := map[string]struct {
+		policy   DropPolicy
+		spans    []sdktrace.ReadOnlySpan
+		expected []string
+	}{
+		"drop new keeps oldest": {
+			policy:   DropNew,
+			spans:    []sdktrace.ReadOnlySpan{stubSpan("a", LogEvent), stubSpan("b", LogEvent), stubSpan("c", LogEvent)},
+			expected: []string{"a", "b"},
+		},
+		"drop oldest keeps newest": {
+			policy:   DropOldest,
+			spans:    []sdktrace.ReadOnlySpan{stubSpan("a", LogEvent), stubSpan("b", LogEvent), stubSpan("c", LogEvent)},
+			expected: []string{"b", "c"},
+		},
+		"drop new evicts lower priority": {
+			policy:   DropNew,
+			spans:    []sdktrace.ReadOnlySpan{stubSpan("a", MetricEvent), stubSpan("b", LogEvent), stubSpan("c", LogEvent)},
+			expected: []string{"b", "c"},
+		},
+		"drop oldest keeps higher priority": {
+			policy:   DropOldest,
+			spans:    []sdktrace.ReadOnlySpan{stubSpan("a", LogEvent), stubSpan("b", LogEvent), stubSpan("c", MetricEvent)},
+			expected: []string{"a", "b"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			exporter := &recordingExporter{}
+			p := newBudgetProcessor(exporter, newMemoryBudget(2*size, tt.policy, nil), time.Second, 128)
+			for _, s := range tt.spans {
+				p.OnEnd(s)
+			}
+			require.NoError(t, p.Shutdown(context.Background()))
+			assert.Equal(t, tt.expected, exporter.names())
+		})
+	}
+}
+
+func TestBudgetSharedByExporters(t *testing.T) {
+	size := estimateSpanSize(stubSpan("a", LogEvent))
+	useConfig(t, &config{})
+	first, second := &recordingExporter{}, &recordingExporter{}
+	targets := []exportTarget{
+		{exporter: first, stats: &exporterStats{name: "first"}},
+		{exporter: second, stats: &exporterStats{name: "second"}},
+	}
+	t.Cleanup(resetExporterStats)
+
+	p := newBudgetExportProcessor(targets, newMemoryBudget(2*size, DropNew, nil))
+	for _, name := range []string{"a", "b", "c"} {
+		p.OnEnd(stubSpan(name, LogEvent))
+	}
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"a", "b"}, first.names())
+	assert.Equal(t, []string{"a", "b"}, second.names())
+	for _, target := range targets {
+		assert.Equal(t, int64(2), target.stats.exported.Load())
+	}
+}
+
+func TestBudgetExportBatching(t *testing.T) {
+	useConfig(t, &config{exportBatchTimeout: time.Hour, exportBatchSize: 2})
+	exporter := &recordingExporter{}
+	targets := []exportTarget{{exporter: exporter, stats: &exporterStats{name: "batched"}}}
+	t.Cleanup(resetExporterStats)
+
+	p := newBudgetExportProcessor(targets, newMemoryBudget(1<<20, DropNew, nil))
+	defer func() { _ = p.Shutdown(context.Background()) }()
+	p.OnEnd(stubSpan("a", ""))
+	// the batch is exported once it holds the configured number of spans, well before the timeout
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, exporter.names())
+	p.OnEnd(stubSpan("b", ""))
+	assert.Eventually(t, func() bool {
+		return len(exporter.names()) == 2
+	}, 500*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestExportProcessorsShareBudget(t *testing.T) {
+	useConfig(t, &config{disableOTLP: true, consoleExporter: true, zipkinEndpoint: "http://localhost:9411/api/v2/spans"})
+	t.Cleanup(resetExporterStats)
+
+	processors, err := newExportProcessors(newMemoryBudget(1<<20, DropNew, nil))
+	require.NoError(t, err)
+	defer shutdownProcessors(processors)
+	// the limit bounds the memory buffered for both exporters, rather than for each of them
+	require.Len(t, processors, 1)
+	assert.Len(t, exporterStatsSnapshot(), 2)
+}
+
+func TestLogsShareBudget(t *testing.T) {
+	useConfig(t, &config{})
+	metric, traced := stubSpan("metric", MetricEvent), stubSpan("traced", "")
+	r := LogRecord{Time: time.Now(), Severity: "INFO", Message: "charged"}
+	budget := newMemoryBudget(0, DropNew, nil)
+	e := newLogExporter("http://localhost", resource.Empty(), budget)
+	exporter := &recordingExporter{}
+	p := newBudgetProcessor(exporter, budget, time.Second, 128)
+	// the budget holds a single span or log
+	budget.limit = max(logSize(e.newRecord(context.Background(), r)), estimateSpanSize(metric), estimateSpanSize(traced))
+	dropped := DroppedTelemetry()
+
+	p.OnEnd(metric)
+	// logs evict metrics, which have a lower priority
+	e.add(context.Background(), r)
+	assert.Len(t, e.queue, 1)
+	// spans evict logs, which have a lower priority
+	p.OnEnd(traced)
+	assert.Empty(t, e.queue)
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"traced"}, exporter.names())
+	assert.Equal(t, dropped[SignalMetric]+1, DroppedTelemetry()[SignalMetric])
+	assert.Equal(t, dropped[SignalLog]+1, DroppedTelemetry()[SignalLog])
+	assert.Equal(t, int64(1), e.stats.failed.Load())
+	assert.Zero(t, budget.size)
+}
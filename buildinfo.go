@@ -0,0 +1,97 @@
+package scout
+
+import (
+	"runtime/debug"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const VCSRevisionAttribute = "vcs.revision"
+const VCSModifiedAttribute = "vcs.modified"
+const VCSTimeAttribute = "vcs.time"
+
+const modulePath = "github.com/scout-inc/scout-go"
+
+// defaultInstrumentationVersion is used when the build info does not record the scout-go version,
+// for example when building from a local checkout.
+const defaultInstrumentationVersion = "v0.1.0"
+
+// instrumentationVersion returns the version of the scout-go module linked into the binary.
+func instrumentationVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return defaultInstrumentationVersion
+	}
+	return moduleVersion(info)
+}
+
+func moduleVersion(info *debug.BuildInfo) string {
+	version := info.Main.Version
+	if info.Main.Path != modulePath {
+		version = ""
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+				if dep.Replace != nil && dep.Replace.Version != "" {
+					version = dep.Replace.Version
+				}
+			}
+		}
+	}
+	if version == "" || version == "(devel)" {
+		return defaultInstrumentationVersion
+	}
+	return version
+}
+
+// WithoutVCSAttributes stops recording the vcs.revision, vcs.time and vcs.modified resource attributes
+// read from the build info, such as for binaries whose commit should not be disclosed.
+func WithoutVCSAttributes() Option {
+	return option(func(conf *config) {
+		conf.disableVCSAttributes = true
+	})
+}
+
+// buildResourceAttributes describes the application binary from its build info: the service version
+// and the VCS revision, commit time and dirtiness of the checkout it was built from. They are overridden
+// by WithServiceVersion and OTEL_RESOURCE_ATTRIBUTES.
+func buildResourceAttributes() []attribute.KeyValue {
+	conf := loadConfig()
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	return buildInfoAttributes(info, !conf.disableVCSAttributes)
+}
+
+func buildInfoAttributes(info *debug.BuildInfo, vcs bool) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	var revision string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+			attrs = append(attrs, attribute.String(VCSRevisionAttribute, setting.Value))
+		case "vcs.time":
+			attrs = append(attrs, attribute.String(VCSTimeAttribute, setting.Value))
+		case "vcs.modified":
+			if modified, err := strconv.ParseBool(setting.Value); err == nil {
+				attrs = append(attrs, attribute.Bool(VCSModifiedAttribute, modified))
+			}
+		}
+	}
+	if !vcs {
+		attrs, revision = nil, ""
+	}
+	// binaries built from a checkout report (devel) as their version, so fall back to the revision
+	version := info.Main.Version
+	if version == "" || version == "(devel)" {
+		version = revision
+	}
+	if version != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(version))
+	}
+	return attrs
+}
@@ -0,0 +1,35 @@
+package scout
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestBuildInfo(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		Deps: []*debug.Module{{Path: modulePath, Version: "v0.3.0"}},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2024-05-06T07:08:09Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	assert.Equal(t, "v0.3.0", moduleVersion(info))
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(VCSRevisionAttribute, "abc123"),
+		attribute.String(VCSTimeAttribute, "2024-05-06T07:08:09Z"),
+		attribute.Bool(VCSModifiedAttribute, true),
+		semconv.ServiceVersionKey.String("abc123"),
+	}, buildInfoAttributes(info, true))
+	assert.Empty(t, buildInfoAttributes(info, false))
+
+	info.Main.Version = "v1.4.0"
+	info.Deps = nil
+	assert.Equal(t, defaultInstrumentationVersion, moduleVersion(info))
+	assert.Contains(t, buildInfoAttributes(info, true), semconv.ServiceVersionKey.String("v1.4.0"))
+}
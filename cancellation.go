@@ -0,0 +1,64 @@
+package scout
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorCancellationAttribute is set on errors caused by a context being canceled, to "canceled" or
+// "deadline_exceeded", so they can be told apart from server faults.
+const ErrorCancellationAttribute = "exception.cancellation"
+
+// ErrorSeverityAttribute is set to "warning" on errors caused by a context being canceled.
+const ErrorSeverityAttribute = "exception.severity"
+
+// WithoutCancellationErrorStatus stops errors caused by a canceled context or an exceeded deadline from
+// setting the error status of spans. They are still recorded, with an exception.cancellation attribute.
+func WithoutCancellationErrorStatus() Option {
+	return runtimeOption(func(conf *config) {
+		conf.ignoreCancellations = true
+	})
+}
+
+// cancellation returns how the context err comes from was done, or an empty string
+// if err is not, and does not wrap, context.Canceled or context.DeadlineExceeded.
+func cancellation(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	}
+	return ""
+}
+
+// IsCancellation reports whether err is, or wraps, context.Canceled or context.DeadlineExceeded.
+func IsCancellation(err error) bool {
+	return cancellation(err) != ""
+}
+
+// cancellationAttributes returns the attributes classifying err if it is a cancellation.
+func cancellationAttributes(err error) []attribute.KeyValue {
+	c := cancellation(err)
+	if c == "" {
+		return nil
+	}
+	return []attribute.KeyValue{
+		attribute.String(ErrorCancellationAttribute, c),
+		attribute.String(ErrorSeverityAttribute, "warning"),
+	}
+}
+
+// SetSpanErrorStatus sets the error status of span for err, unless err is a cancellation and
+// WithoutCancellationErrorStatus is set.
+func SetSpanErrorStatus(span trace.Span, err error) {
+	conf := loadConfig()
+	if err == nil || conf.ignoreCancellations && IsCancellation(err) {
+		return
+	}
+	span.SetStatus(codes.Error, err.Error())
+}
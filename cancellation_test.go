@@ -0,0 +1,36 @@
+package scout
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestCancellation(t *testing.T) {
+	recorder := recordSpans(t)
+	useConfig(t, &config{})
+
+	canceled := fmt.Errorf("querying users: %w", context.Canceled)
+	_ = Trace(context.Background(), "canceled", func(context.Context) error { return canceled })
+	updateConfig(WithoutCancellationErrorStatus().apply)
+	_ = Trace(context.Background(), "deadline", func(context.Context) error { return context.DeadlineExceeded })
+	_ = Trace(context.Background(), "failed", func(context.Context) error { return fmt.Errorf("failed") })
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Events()[0].Attributes, attribute.String(ErrorCancellationAttribute, "canceled"))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Events()[0].Attributes, attribute.String(ErrorCancellationAttribute, "deadline_exceeded"))
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.NotContains(t, spans[2].Events()[0].Attributes, attribute.String(ErrorSeverityAttribute, "warning"))
+
+	// cancellations do not count towards error rate watchers
+	assert.Zero(t, spanErrors(spans[0]))
+	assert.Equal(t, 1., spanErrors(spans[2]))
+}
@@ -0,0 +1,124 @@
+package scout
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupV1UnlimitedBytes is the lower bound of the memory limit reported by cgroup v1 when there is no limit.
+const cgroupV1UnlimitedBytes = math.MaxInt64 &^ 0xfff
+
+// cgroupMemory returns the memory usage and limit of the container, from cgroup v2 or v1.
+// ok is false if they are unavailable or the container has no memory limit.
+func cgroupMemory() (usage, limit int64, ok bool) {
+	// cgroup v2
+	if max, err := readCgroupFile("memory.max"); err == nil {
+		if max == "max" {
+			return 0, 0, false
+		}
+		limit, err1 := strconv.ParseInt(max, 10, 64)
+		usage, err2 := readCgroupInt("memory.current")
+		return usage, limit, err1 == nil && err2 == nil && limit > 0
+	}
+	// cgroup v1
+	limit, err := readCgroupInt(filepath.Join("memory", "memory.limit_in_bytes"))
+	if err != nil || limit <= 0 || limit >= cgroupV1UnlimitedBytes {
+		return 0, 0, false
+	}
+	usage, err = readCgroupInt(filepath.Join("memory", "memory.usage_in_bytes"))
+	return usage, limit, err == nil
+}
+
+func readCgroupFile(name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(cgroupRoot, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readCgroupInt(name string) (int64, error) {
+	value, err := readCgroupFile(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// cgroupCPUStats are the cumulative CPU throttling counters of the container.
+type cgroupCPUStats struct {
+	periods          int64
+	throttledPeriods int64
+	throttledTime    time.Duration
+}
+
+// cgroupCPU reads the CPU throttling counters from cpu.stat of cgroup v2 or v1.
+func cgroupCPU() (cgroupCPUStats, bool) {
+	// cgroup v2 reports throttled time in microseconds
+	if stats, err := readCgroupStat("cpu.stat"); err == nil {
+		if _, ok := stats["nr_periods"]; ok {
+			return cgroupCPUStats{
+				periods:          stats["nr_periods"],
+				throttledPeriods: stats["nr_throttled"],
+				throttledTime:    time.Duration(stats["throttled_usec"]) * time.Microsecond,
+			}, true
+		}
+	}
+	// cgroup v1 reports throttled time in nanoseconds
+	if stats, err := readCgroupStat(filepath.Join("cpu", "cpu.stat")); err == nil {
+		return cgroupCPUStats{
+			periods:          stats["nr_periods"],
+			throttledPeriods: stats["nr_throttled"],
+			throttledTime:    time.Duration(stats["throttled_time"]),
+		}, true
+	}
+	return cgroupCPUStats{}, false
+}
+
+// cgroupMemoryPressure returns the share of the last 10 seconds in which some tasks of the container
+// were stalled waiting for memory, from the cgroup v2 pressure stall information.
+func cgroupMemoryPressure() (float64, bool) {
+	pressure, err := readCgroupFile("memory.pressure")
+	if err != nil {
+		return 0, false
+	}
+	for _, line := range strings.Split(pressure, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(field, "avg10="); ok {
+				avg10, err := strconv.ParseFloat(value, 64)
+				return avg10 / 100, err == nil
+			}
+		}
+	}
+	return 0, false
+}
+
+// readCgroupStat parses a flat keyed cgroup file such as cpu.stat.
+func readCgroupStat(name string) (map[string]int64, error) {
+	content, err := readCgroupFile(name)
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]int64{}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			stats[key] = n
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,63 @@
+package scout
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+)
+
+const (
+	ContainerCPUPeriodsMetric          = "container.cpu.periods"
+	ContainerCPUThrottledPeriodsMetric = "container.cpu.throttled_periods"
+	ContainerCPUThrottledTimeMetric    = "container.cpu.throttled_time"
+	ContainerMemoryUsageMetric         = "container.memory.usage"
+	ContainerMemoryLimitMetric         = "container.memory.limit"
+	ContainerMemoryUtilizationMetric   = "container.memory.utilization"
+	ContainerMemoryPressureMetric      = "container.memory.pressure"
+)
+
+// WithCgroupMetrics records the CPU throttling and memory usage of the container from its cgroup every
+// interval, so throttling imposed by the orchestrator can be correlated with latency. CPU periods and
+// throttled time are cumulative, throttled time is in seconds, and memory pressure is the share of the
+// last 10 seconds in which tasks stalled on memory, where the kernel reports it.
+func WithCgroupMetrics(interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.cgroupMetricsPeriod = interval
+	})
+}
+
+func startCgroupMetrics() {
+	conf := loadConfig()
+	if conf.cgroupMetricsPeriod <= 0 {
+		return
+	}
+	_, cpuOK := cgroupCPU()
+	_, _, memoryOK := cgroupMemory()
+	if !cpuOK && !memoryOK {
+		logger.Warnf("cgroup metrics disabled: no cgroup statistics found")
+		return
+	}
+	startPeriodic(conf.cgroupMetricsPeriod, recordCgroupMetrics)
+}
+
+func recordCgroupMetrics(ctx context.Context) {
+	span, _ := startInternalTrace(ctx, ScopedKey("cgroup-metrics", ptr.String("-")))
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return
+	}
+	if cpu, ok := cgroupCPU(); ok {
+		addGaugeEvent(span, ContainerCPUPeriodsMetric, float64(cpu.periods))
+		addGaugeEvent(span, ContainerCPUThrottledPeriodsMetric, float64(cpu.throttledPeriods))
+		addGaugeEvent(span, ContainerCPUThrottledTimeMetric, cpu.throttledTime.Seconds())
+	}
+	if usage, limit, ok := cgroupMemory(); ok {
+		addGaugeEvent(span, ContainerMemoryUsageMetric, float64(usage))
+		addGaugeEvent(span, ContainerMemoryLimitMetric, float64(limit))
+		addGaugeEvent(span, ContainerMemoryUtilizationMetric, float64(usage)/float64(limit))
+	}
+	if pressure, ok := cgroupMemoryPressure(); ok {
+		addGaugeEvent(span, ContainerMemoryPressureMetric, pressure)
+	}
+}
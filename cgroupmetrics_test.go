@@ -0,0 +1,47 @@
+package scout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCgroupMetrics(t *testing.T) {
+	recorder := recordSpans(t)
+	writeCgroupFiles(t, map[string]string{
+		"cpu.stat":        "usage_usec 100\nnr_periods 40\nnr_throttled 10\nthrottled_usec 2500000",
+		"memory.max":      "1000",
+		"memory.current":  "250",
+		"memory.pressure": "some avg10=1.50 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0",
+	})
+
+	recordCgroupMetrics(context.Background())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	values := map[string]float64{}
+	for _, event := range spans[0].Events() {
+		var name string
+		var value float64
+		for _, kv := range event.Attributes {
+			switch kv.Key {
+			case MetricEventName:
+				name = kv.Value.AsString()
+			case MetricEventValue:
+				value = kv.Value.AsFloat64()
+			}
+		}
+		values[name] = value
+	}
+	assert.Equal(t, map[string]float64{
+		ContainerCPUPeriodsMetric:          40,
+		ContainerCPUThrottledPeriodsMetric: 10,
+		ContainerCPUThrottledTimeMetric:    2.5,
+		ContainerMemoryUsageMetric:         250,
+		ContainerMemoryLimitMetric:         1000,
+		ContainerMemoryUtilizationMetric:   0.25,
+		ContainerMemoryPressureMetric:      0.015,
+	}, values)
+}
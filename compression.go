@@ -0,0 +1,115 @@
+package scout
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Compression is the payload compression used when exporting telemetry.
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd falls back to gzip if the endpoint rejects zstd payloads.
+	CompressionZstd Compression = "zstd"
+)
+
+// WithCompression sets the compression used for OTLP export. The default is gzip.
+func WithCompression(compression Compression) Option {
+	return option(func(conf *config) {
+		conf.compression = compression
+	})
+}
+
+// zstdClient is an OTLP/HTTP trace client sending zstd compressed protobuf payloads,
+// which the upstream otlptracehttp client does not support.
+type zstdClient struct {
+	url          string
+	headers      map[string]string
+	client       *http.Client
+	encoder      *zstd.Encoder
+	gzipFallback atomic.Bool
+}
+
+var _ otlptrace.Client = (*zstdClient)(nil)
+
+func newZstdClient(endpoint string) (*zstdClient, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating zstd encoder: %w", err)
+	}
+	return &zstdClient{
+		url:     endpoint + "/v1/traces",
+		headers: otlpHeaders(),
+		client:  &http.Client{Timeout: exportRequestTimeout()},
+		encoder: encoder,
+	}, nil
+}
+
+func (c *zstdClient) Start(context.Context) error {
+	return nil
+}
+
+func (c *zstdClient) Stop(context.Context) error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func (c *zstdClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("marshaling OTLP trace request: %w", err)
+	}
+	if !c.gzipFallback.Load() {
+		status, err := c.post(ctx, CompressionZstd, c.encoder.EncodeAll(body, nil))
+		if status != http.StatusUnsupportedMediaType {
+			return err
+		}
+		logger.Warnf("otlp endpoint %s does not support zstd, falling back to gzip", c.url)
+		c.gzipFallback.Store(true)
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return fmt.Errorf("compressing OTLP trace request: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compressing OTLP trace request: %w", err)
+	}
+	_, err = c.post(ctx, CompressionGzip, buf.Bytes())
+	return err
+}
+
+func (c *zstdClient) post(ctx context.Context, encoding Compression, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating OTLP trace request: %w", err)
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", string(encoding))
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending OTLP trace request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("OTLP trace request failed: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
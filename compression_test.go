@@ -0,0 +1,105 @@
+package scout
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var testResourceSpans = []*tracepb.ResourceSpans{{
+	ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{Name: "charge"}}}},
+}}
+
+// decodeTraceRequest decompresses and decodes an OTLP trace request according to its Content-Encoding.
+func decodeTraceRequest(t *testing.T, r *http.Request) *coltracepb.ExportTraceServiceRequest {
+	var body io.Reader = r.Body
+	switch r.Header.Get("Content-Encoding") {
+	case "zstd":
+		dec, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+		defer dec.Close()
+		body = dec
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body = gz
+	}
+	raw, err := io.ReadAll(body)
+	require.NoError(t, err)
+	req := &coltracepb.ExportTraceServiceRequest{}
+	require.NoError(t, proto.Unmarshal(raw, req))
+	return req
+}
+
+func TestZstdClient(t *testing.T) {
+	t.Setenv(envOTLPHeaders, "authorization=Bearer%20token")
+	useConfig(t, &config{exportTimeout: 3 * time.Second})
+
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := decodeTraceRequest(t, r)
+		assert.Equal(t, "charge", req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+		requests = append(requests, r)
+	}))
+	defer server.Close()
+
+	c, err := newZstdClient(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, c.client.Timeout)
+	require.NoError(t, c.UploadTraces(context.Background(), testResourceSpans))
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/v1/traces", requests[0].URL.Path)
+	assert.Equal(t, "zstd", requests[0].Header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", requests[0].Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("authorization"))
+}
+
+func TestZstdClientGzipFallback(t *testing.T) {
+	useConfig(t, &config{})
+
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		if r.Header.Get("Content-Encoding") == "zstd" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		req := decodeTraceRequest(t, r)
+		assert.Equal(t, "charge", req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	}))
+	defer server.Close()
+
+	c, err := newZstdClient(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, c.UploadTraces(context.Background(), testResourceSpans))
+	require.NoError(t, c.UploadTraces(context.Background(), testResourceSpans))
+	assert.Equal(t, []string{"zstd", "gzip", "gzip"}, encodings, "zstd is not retried once rejected")
+}
+
+func TestZstdClientError(t *testing.T) {
+	useConfig(t, &config{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, err := newZstdClient(server.URL)
+	require.NoError(t, err)
+	err = c.UploadTraces(context.Background(), testResourceSpans)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.False(t, c.gzipFallback.Load())
+}
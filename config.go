@@ -0,0 +1,85 @@
+package scout
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// configMutex serializes changes to the configuration. The configuration is an immutable snapshot:
+// changes are applied to a copy, which then replaces the active configuration, so readers never see a
+// partial update.
+var configMutex sync.Mutex
+
+// activeConfig holds the current configuration snapshot. Each operation loads it once with loadConfig,
+// so it reads consistent settings even if the configuration is replaced meanwhile.
+var activeConfig atomic.Pointer[config]
+
+// loadConfig returns the current configuration snapshot, which must not be modified.
+func loadConfig() *config {
+	return activeConfig.Load()
+}
+
+// runtimeOption is an Option that takes effect while Scout is running, so it can be passed to Reconfigure.
+type runtimeOption func(conf *config)
+
+func (fn runtimeOption) apply(conf *config) {
+	fn(conf)
+}
+
+// clone returns a copy of the configuration which options can change without affecting c.
+func (c *config) clone() *config {
+	next := *c
+	next.samplingRateMap = maps.Clone(c.samplingRateMap)
+	next.attributeAllowlist = maps.Clone(c.attributeAllowlist)
+	next.attributeDenylist = maps.Clone(c.attributeDenylist)
+	// clipping the slices makes options appending to them allocate rather than share the backing arrays
+	next.resourceAttributes = slices.Clip(c.resourceAttributes)
+	next.signalPriority = slices.Clip(c.signalPriority)
+	next.spanProcessors = slices.Clip(c.spanProcessors)
+	next.scrubPatterns = slices.Clip(c.scrubPatterns)
+	next.scrubbedAttributes = slices.Clip(c.scrubbedAttributes)
+	next.redactedQueryParams = slices.Clip(c.redactedQueryParams)
+	next.failoverEndpoints = slices.Clip(c.failoverEndpoints)
+	next.runtimeMetrics = slices.Clip(c.runtimeMetrics)
+	next.slowSpanThresholds = slices.Clip(c.slowSpanThresholds)
+	next.requestHeaders = slices.Clip(c.requestHeaders)
+	next.responseHeaders = slices.Clip(c.responseHeaders)
+	next.redactedHeaders = slices.Clip(c.redactedHeaders)
+	next.alertWatchers = slices.Clip(c.alertWatchers)
+	next.enrichmentRules = slices.Clip(c.enrichmentRules)
+	return &next
+}
+
+// updateConfig replaces the configuration with a copy changed by fn.
+func updateConfig(fn func(conf *config)) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	next := loadConfig().clone()
+	fn(next)
+	activeConfig.Store(next)
+}
+
+// Reconfigure applies options while Scout is running. Only options taking effect at runtime are
+// accepted: WithProjectID, WithMetricSamplingRate, WithRequestIDGeneration, WithGoroutineDumps,
+// WithDatadogPropagation, WithRedactedQueryParams, WithRequestHeaders, WithResponseHeaders,
+// WithRedactedHeaders, WithStatusClassifier, WithoutCancellationErrorStatus, WithMetricNameLowercasing and
+// WithLogTruncation.
+// Other options configure the exporter and processors built on start, and require restarting Scout.
+// If any option is not accepted, none are applied.
+func Reconfigure(opts ...Option) error {
+	for i, opt := range opts {
+		if _, ok := opt.(runtimeOption); !ok {
+			return errors.Errorf("option %d can only be set on start", i)
+		}
+	}
+	updateConfig(func(next *config) {
+		for _, opt := range opts {
+			opt.apply(next)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,83 @@
+package scout
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useConfig makes c the active configuration for the duration of the test.
+func useConfig(t *testing.T, c *config) {
+	prev := loadConfig()
+	activeConfig.Store(c)
+	t.Cleanup(func() { activeConfig.Store(prev) })
+}
+
+func TestReconfigure(t *testing.T) {
+	useConfig(t, &config{projectID: "before"})
+	snapshot := loadConfig()
+
+	require.NoError(t, Reconfigure(WithProjectID("after"), WithRedactedQueryParams("Token")))
+	assert.Equal(t, "after", GetProjectID())
+	assert.Equal(t, []string{"token"}, loadConfig().redactedQueryParams)
+	assert.Equal(t, "before", snapshot.projectID, "the previous snapshot must not change")
+
+	require.Error(t, Reconfigure(WithProjectID("ignored"), WithZipkinEndpoint("http://localhost:9411")))
+	assert.Equal(t, "after", GetProjectID())
+}
+
+func TestConfigClone(t *testing.T) {
+	c := &config{redactedQueryParams: make([]string, 1, 4)}
+	WithAttributeDenylist("secret").apply(c)
+
+	next := c.clone()
+	WithAttributeDenylist("password").apply(next)
+	WithRedactedQueryParams("token").apply(next)
+	assert.Len(t, c.attributeDenylist, 1)
+	assert.Len(t, c.redactedQueryParams, 1)
+	assert.Len(t, next.attributeDenylist, 2)
+	assert.Equal(t, []string{"", "token"}, next.redactedQueryParams)
+	c.redactedQueryParams = append(c.redactedQueryParams, "other")
+	assert.Equal(t, []string{"", "token"}, next.redactedQueryParams)
+}
+
+func TestReconfigureConcurrently(t *testing.T) {
+	useConfig(t, &config{projectID: "before"})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, Reconfigure(WithProjectID(fmt.Sprintf("project-%d", i)), WithRedactedQueryParams("token")))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.NotEmpty(t, ProjectIDFromContext(ctx))
+			isRedactedQueryParam("token")
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, "project-99", GetProjectID())
+}
+
+func TestStartWhileRunning(t *testing.T) {
+	useConfig(t, &config{projectID: "before"})
+	prev := loadState()
+	storeState(started)
+	defer storeState(prev)
+
+	assert.False(t, start([]Option{WithProjectID("ignored"), WithZipkinEndpoint("http://localhost:9411")}))
+	assert.Equal(t, "before", GetProjectID())
+	assert.Empty(t, loadConfig().zipkinEndpoint)
+
+	assert.False(t, start([]Option{WithProjectID("after")}))
+	assert.Equal(t, "after", GetProjectID())
+}
@@ -0,0 +1,63 @@
+package scout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// WithConsoleExporter also writes a line per exported span to stderr, with its duration, trace ID and
+// errors, to follow the telemetry of an application in development.
+func WithConsoleExporter() Option {
+	return option(func(conf *config) {
+		conf.consoleExporter = true
+	})
+}
+
+// consoleExporter writes spans as lines of text.
+type consoleExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var _ sdktrace.SpanExporter = (*consoleExporter)(nil)
+
+func newConsoleExporter() *consoleExporter {
+	return &consoleExporter{w: os.Stderr}
+}
+
+func (e *consoleExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range spans {
+		line := fmt.Sprintf("scout: %s %s trace=%s", s.Name(), s.EndTime().Sub(s.StartTime()).Round(time.Microsecond), s.SpanContext().TraceID())
+		if s.Status().Code == codes.Error {
+			line += fmt.Sprintf(" status=error %q", s.Status().Description)
+		}
+		for _, event := range s.Events() {
+			if event.Name != semconv.ExceptionEventName {
+				continue
+			}
+			for _, kv := range event.Attributes {
+				if kv.Key == semconv.ExceptionMessageKey {
+					line += fmt.Sprintf(" error=%q", kv.Value.AsString())
+				}
+			}
+		}
+		if _, err := fmt.Fprintln(e.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *consoleExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,21 @@
+package scout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleExporter(t *testing.T) {
+	recorder := recordSpans(t)
+	RecordError(context.Background(), errors.New("payment declined"))
+
+	var b bytes.Buffer
+	require.NoError(t, (&consoleExporter{w: &b}).ExportSpans(context.Background(), recorder.Ended()))
+	assert.Contains(t, b.String(), "scout: scout-ctx ")
+	assert.Contains(t, b.String(), `error="payment declined"`)
+}
@@ -0,0 +1,109 @@
+package scout
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const DatadogAgentDefaultEndpoint = "http://localhost:4318"
+
+const (
+	datadogTraceIDHeader          = "x-datadog-trace-id"
+	datadogParentIDHeader         = "x-datadog-parent-id"
+	datadogSamplingPriorityHeader = "x-datadog-sampling-priority"
+	datadogTagsHeader             = "x-datadog-tags"
+	// datadogTraceIDUpperTag carries the upper 64 bits of 128-bit trace IDs as hex
+	datadogTraceIDUpperTag = "_dd.p.tid"
+)
+
+// WithDatadogAgent additionally exports spans to the OTLP/HTTP receiver of a Datadog agent,
+// so services can ship to both Scout and Datadog during a migration. Use DatadogAgentDefaultEndpoint
+// for an agent running alongside the service.
+func WithDatadogAgent(endpoint string) Option {
+	return option(func(conf *config) {
+		conf.datadogAgentEndpoint = endpoint
+	})
+}
+
+// WithDatadogPropagation adds DatadogPropagator to the propagators returned by NewPropagator.
+func WithDatadogPropagation() Option {
+	return runtimeOption(func(conf *config) {
+		conf.datadogPropagation = true
+	})
+}
+
+// DatadogPropagator propagates trace context using the Datadog x-datadog-* headers.
+type DatadogPropagator struct{}
+
+var _ propagation.TextMapPropagator = DatadogPropagator{}
+
+// Inject sets the Datadog headers from the span context in ctx.
+func (DatadogPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	traceID := sc.TraceID()
+	spanID := sc.SpanID()
+	carrier.Set(datadogTraceIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10))
+	carrier.Set(datadogParentIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10))
+	carrier.Set(datadogTagsHeader, fmt.Sprintf("%s=%016x", datadogTraceIDUpperTag, binary.BigEndian.Uint64(traceID[:8])))
+	priority := "0"
+	if sc.IsSampled() {
+		priority = "1"
+	}
+	carrier.Set(datadogSamplingPriorityHeader, priority)
+}
+
+// Extract reads the Datadog headers into a remote span context.
+func (DatadogPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	lower, err := strconv.ParseUint(carrier.Get(datadogTraceIDHeader), 10, 64)
+	if err != nil {
+		return ctx
+	}
+	parent, err := strconv.ParseUint(carrier.Get(datadogParentIDHeader), 10, 64)
+	if err != nil {
+		return ctx
+	}
+
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(traceID[8:], lower)
+	binary.BigEndian.PutUint64(spanID[:], parent)
+	for _, tag := range strings.Split(carrier.Get(datadogTagsHeader), ",") {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key != datadogTraceIDUpperTag {
+			continue
+		}
+		if upper, err := strconv.ParseUint(value, 16, 64); err == nil {
+			binary.BigEndian.PutUint64(traceID[:8], upper)
+		}
+	}
+
+	var flags trace.TraceFlags
+	// priorities of 1 (auto keep) and 2 (user keep) are sampled
+	if priority, err := strconv.Atoi(carrier.Get(datadogSamplingPriorityHeader)); err == nil && priority > 0 {
+		flags = trace.FlagsSampled
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the headers used by the propagator.
+func (DatadogPropagator) Fields() []string {
+	return []string{datadogTraceIDHeader, datadogParentIDHeader, datadogSamplingPriorityHeader, datadogTagsHeader}
+}
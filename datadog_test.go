@@ -0,0 +1,44 @@
+package scout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDatadogPropagator(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	carrier := propagation.MapCarrier{}
+	DatadogPropagator{}.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+	assert.Equal(t, "651345242494996240", carrier.Get(datadogTraceIDHeader))
+	assert.Equal(t, "72623859790382856", carrier.Get(datadogParentIDHeader))
+	assert.Equal(t, "1", carrier.Get(datadogSamplingPriorityHeader))
+
+	extracted := trace.SpanContextFromContext(DatadogPropagator{}.Extract(context.Background(), carrier))
+	assert.Equal(t, traceID, extracted.TraceID())
+	assert.Equal(t, spanID, extracted.SpanID())
+	assert.True(t, extracted.IsSampled())
+	assert.True(t, extracted.IsRemote())
+
+	empty := DatadogPropagator{}.Extract(context.Background(), propagation.MapCarrier{})
+	assert.False(t, trace.SpanContextFromContext(empty).IsValid())
+}
+
+func TestGlobalPropagatorReconfigured(t *testing.T) {
+	useConfig(t, &config{})
+	assert.NotContains(t, globalPropagator{}.Fields(), "x-datadog-trace-id")
+
+	require.NoError(t, Reconfigure(WithDatadogPropagation()))
+	assert.Contains(t, globalPropagator{}.Fields(), "x-datadog-trace-id")
+}
@@ -0,0 +1,169 @@
+package scout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// debugStreamBuffer is the number of spans buffered per debug stream client, beyond which spans are
+// skipped for that client rather than slowing down the application.
+const debugStreamBuffer = 256
+
+type debugSpan struct {
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind"`
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	ParentSpanID  string         `json:"parent_span_id,omitempty"`
+	Start         time.Time      `json:"start"`
+	DurationMs    float64        `json:"duration_ms"`
+	Status        string         `json:"status"`
+	StatusMessage string         `json:"status_message,omitempty"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Events        []debugEvent   `json:"events,omitempty"`
+}
+
+type debugEvent struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// debugStream broadcasts the sampled spans to the clients of DebugStreamHandler.
+type debugStream struct {
+	clients atomic.Int32
+
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+}
+
+var _ sdktrace.SpanProcessor = (*debugStream)(nil)
+
+// debugSpans is registered with the tracer provider on start, and costs nothing until a client connects.
+var debugSpans = &debugStream{subscribers: map[chan []byte]struct{}{}}
+
+func (d *debugStream) subscribe() chan []byte {
+	ch := make(chan []byte, debugStreamBuffer)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers[ch] = struct{}{}
+	d.clients.Add(1)
+	return ch
+}
+
+func (d *debugStream) unsubscribe(ch chan []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.subscribers, ch)
+	d.clients.Add(-1)
+}
+
+func (d *debugStream) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (d *debugStream) OnEnd(s sdktrace.ReadOnlySpan) {
+	if d.clients.Load() == 0 || !s.SpanContext().IsSampled() {
+		return
+	}
+	event := "span"
+	span := debugSpan{
+		Name:          s.Name(),
+		Kind:          s.SpanKind().String(),
+		TraceID:       s.SpanContext().TraceID().String(),
+		SpanID:        s.SpanContext().SpanID().String(),
+		Start:         s.StartTime(),
+		DurationMs:    float64(s.EndTime().Sub(s.StartTime())) / float64(time.Millisecond),
+		Status:        s.Status().Code.String(),
+		StatusMessage: s.Status().Description,
+		Attributes:    map[string]any{},
+	}
+	if s.Parent().HasSpanID() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	for _, kv := range s.Attributes() {
+		span.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	for _, e := range s.Events() {
+		// spans recording an error are streamed as error events, so clients can follow errors alone
+		if e.Name == semconv.ExceptionEventName {
+			event = "error"
+		}
+		attrs := make(map[string]any, len(e.Attributes))
+		for _, kv := range e.Attributes {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		span.Events = append(span.Events, debugEvent{Name: e.Name, Time: e.Time, Attributes: attrs})
+	}
+	data, err := json.Marshal(span)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	msg := []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event, data))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for ch := range d.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+func (d *debugStream) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (d *debugStream) Shutdown(context.Context) error {
+	return nil
+}
+
+// DebugStreamHandler returns a handler streaming the sampled spans as they end, as server-sent events
+// holding the span as JSON. Spans recording an error are sent as error events and the others as span
+// events, so developers can watch the telemetry of the application live, for example with:
+//
+//	curl -N localhost:6061
+//
+// Spans are streamed as recorded, before data scrubbing, so the handler must not be exposed publicly.
+func DebugStreamHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		ch := debugSpans.subscribe()
+		defer debugSpans.unsubscribe(ch)
+		_, _ = fmt.Fprint(w, ": streaming scout telemetry\n\n")
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case msg := <-ch:
+				if _, err := w.Write(msg); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// ServeDebugStream listens on addr and serves DebugStreamHandler, blocking like http.ListenAndServe.
+// It is meant for local development, so addr should be a loopback address:
+//
+//	go scout.ServeDebugStream("localhost:6061")
+func ServeDebugStream(addr string) error {
+	return http.ListenAndServe(addr, DebugStreamHandler())
+}
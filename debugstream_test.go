@@ -0,0 +1,59 @@
+package scout
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDebugStream(t *testing.T) {
+	server := httptest.NewServer(DebugStreamHandler())
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := bufio.NewScanner(resp.Body)
+	// the handler subscribes before writing its greeting
+	require.True(t, lines.Scan())
+
+	sampled := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	debugSpans.OnEnd(tracetest.SpanStub{Name: "dropped"}.Snapshot())
+	debugSpans.OnEnd(tracetest.SpanStub{Name: "query", SpanContext: sampled}.Snapshot())
+	debugSpans.OnEnd(tracetest.SpanStub{
+		Name:        "failed",
+		SpanContext: sampled,
+		Events:      []sdktrace.Event{{Name: semconv.ExceptionEventName}},
+	}.Snapshot())
+
+	var events, names []string
+	for len(names) < 2 && lines.Scan() {
+		line := lines.Text()
+		if event, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, event)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var span debugSpan
+			require.NoError(t, json.Unmarshal([]byte(data), &span))
+			names = append(names, span.Name)
+		}
+	}
+	assert.Equal(t, []string{"span", "error"}, events)
+	assert.Equal(t, []string{"query", "failed"}, names)
+}
@@ -0,0 +1,44 @@
+package scout
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/smithy-go/ptr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const DeployEvent = "deploy"
+const DeployVersionAttribute = "deploy.version"
+const DeploySHAAttribute = "deploy.sha"
+
+// deployMetadataPrefix namespaces the metadata passed to RecordDeploy.
+const deployMetadataPrefix = "deploy."
+
+// RecordDeploy records a deploy of the service, which Scout uses to draw release markers on charts
+// and attribute new errors to the release that introduced them.
+// Call it once on startup of the new release, for example:
+//
+//	scout.RecordDeploy(version, commitSHA, map[string]string{"environment": "production"})
+func RecordDeploy(version, sha string, metadata map[string]string) {
+	attrs := []attribute.KeyValue{
+		attribute.String(DeployVersionAttribute, version),
+		attribute.String(DeploySHAAttribute, sha),
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attrs = append(attrs, attribute.String(deployMetadataPrefix+key, metadata[key]))
+	}
+
+	span, _ := startInternalTrace(context.Background(), ScopedKey("deploy", ptr.String("-")))
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(DeployEvent, trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,27 @@
+package scout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRecordDeploy(t *testing.T) {
+	recorder := recordSpans(t)
+
+	RecordDeploy("v1.2.0", "abc123", map[string]string{"environment": "production", "actor": "ci"})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, DeployEvent, events[0].Name)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(DeployVersionAttribute, "v1.2.0"),
+		attribute.String(DeploySHAAttribute, "abc123"),
+		attribute.String("deploy.actor", "ci"),
+		attribute.String("deploy.environment", "production"),
+	}, events[0].Attributes)
+}
@@ -0,0 +1,110 @@
+package scout
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type diagnostics struct {
+	State     string                `json:"state"`
+	Config    diagnosticsConfig     `json:"config"`
+	Exporters []exporterDiagnostics `json:"exporters"`
+	Dropped   map[Signal]int64      `json:"dropped"`
+}
+
+type diagnosticsConfig struct {
+	ProjectID          string             `json:"project_id"`
+	OTLPEndpoint       string             `json:"otlp_endpoint,omitempty"`
+	MetricSamplingRate float64            `json:"metric_sampling_rate"`
+	SamplingRates      map[string]float64 `json:"sampling_rates,omitempty"`
+	Compression        Compression        `json:"compression,omitempty"`
+	MemoryLimit        int64              `json:"memory_limit,omitempty"`
+	DataScrubbing      bool               `json:"data_scrubbing"`
+	Anonymization      bool               `json:"anonymization"`
+}
+
+type exporterDiagnostics struct {
+	Name       string     `json:"name"`
+	Endpoint   string     `json:"endpoint"`
+	Healthy    bool       `json:"healthy"`
+	Exported   int64      `json:"exported"`
+	Failed     int64      `json:"failed"`
+	Pending    int64      `json:"pending"`
+	LastError  string     `json:"last_error,omitempty"`
+	LastExport *time.Time `json:"last_export,omitempty"`
+}
+
+// DiagnosticsHandler returns a handler reporting the SDK state, the configuration, the health of
+// each exporter and the telemetry dropped because of the memory limit, as JSON.
+// Secrets such as the project ID and endpoint credentials are redacted. It is intended to be mounted
+// at /debug/scout.
+func DiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(collectDiagnostics())
+	})
+}
+
+func collectDiagnostics() diagnostics {
+	conf := loadConfig()
+	d := diagnostics{
+		State:     loadState().String(),
+		Exporters: []exporterDiagnostics{},
+		Dropped:   DroppedTelemetry(),
+	}
+
+	d.Config = diagnosticsConfig{
+		MetricSamplingRate: conf.metricSamplingRate,
+		Compression:        conf.compression,
+		MemoryLimit:        conf.memoryLimit,
+		DataScrubbing:      conf.dataScrubbing,
+		Anonymization:      conf.anonymization,
+	}
+	if conf.projectID != "" {
+		d.Config.ProjectID = RedactedValue
+	}
+	if !conf.disableOTLP {
+		d.Config.OTLPEndpoint = redactEndpoint(otlpEndpoint())
+	}
+	if len(conf.samplingRateMap) > 0 {
+		d.Config.SamplingRates = make(map[string]float64, len(conf.samplingRateMap))
+		for kind, rate := range conf.samplingRateMap {
+			d.Config.SamplingRates[kind.String()] = rate
+		}
+	}
+
+	for _, stats := range exporterStatsSnapshot() {
+		lastExport, lastError := stats.lastResult()
+		exporter := exporterDiagnostics{
+			Name:     stats.name,
+			Endpoint: redactEndpoint(stats.endpoint),
+			// an exporter is healthy until its most recent export fails
+			Healthy:  lastError == nil,
+			Exported: stats.exported.Load(),
+			Failed:   stats.failed.Load(),
+			Pending:  stats.pending(),
+		}
+		if lastError != nil {
+			exporter.LastError = lastError.Error()
+		}
+		if !lastExport.IsZero() {
+			exporter.LastExport = &lastExport
+		}
+		d.Exporters = append(d.Exporters, exporter)
+	}
+	return d
+}
+
+// redactEndpoint strips credentials and sensitive query parameters from an endpoint.
+func redactEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return RedactURL(u).String()
+}
@@ -0,0 +1,172 @@
+package scout
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// SpanMatcher selects the spans enriched by WithSpanEnrichment and WithSpanEnricher. Spans are matched
+// when they start, with the name and the attributes set on start, and the spans not matched then are
+// matched again when they end, once instrumentation such as the Scout middlewares has named them.
+type SpanMatcher func(span sdktrace.ReadOnlySpan) bool
+
+// MatchSpanName matches the spans whose name matches pattern.
+func MatchSpanName(pattern *regexp.Regexp) SpanMatcher {
+	return func(span sdktrace.ReadOnlySpan) bool {
+		return pattern.MatchString(span.Name())
+	}
+}
+
+// MatchSpanAttribute matches the spans started with the attribute kv.
+func MatchSpanAttribute(kv attribute.KeyValue) SpanMatcher {
+	return func(span sdktrace.ReadOnlySpan) bool {
+		for _, attr := range span.Attributes() {
+			if attr.Key == kv.Key {
+				return attr.Value == kv.Value
+			}
+		}
+		return false
+	}
+}
+
+type enrichmentRule struct {
+	// match selects the spans enriched, or every span when nil
+	match  SpanMatcher
+	attrs  []attribute.KeyValue
+	enrich func(span sdktrace.ReadWriteSpan)
+}
+
+// WithSpanEnrichment sets attrs on every span matched by match, or on every span if match is nil, so
+// tagging policies live in one place rather than in each handler. For example:
+//
+//	scout.WithSpanEnrichment(scout.MatchSpanName(regexp.MustCompile(`^/api/payments/`)), attribute.String("team", "payments"))
+func WithSpanEnrichment(match SpanMatcher, attrs ...attribute.KeyValue) Option {
+	return option(func(conf *config) {
+		conf.enrichmentRules = append(conf.enrichmentRules, enrichmentRule{match: match, attrs: attrs})
+	})
+}
+
+// WithSpanEnricher calls enrich with every span matched by match, or with every span if match is nil, for
+// attributes computed from the span. Rules are applied in the order they were added. A span matched when
+// it ends is already ended, so enrich can only set its attributes, add events and set its status, and
+// only the exported span is changed, not the span seen by the processors added with WithSpanProcessor.
+func WithSpanEnricher(match SpanMatcher, enrich func(span sdktrace.ReadWriteSpan)) Option {
+	return option(func(conf *config) {
+		conf.enrichmentRules = append(conf.enrichmentRules, enrichmentRule{match: match, enrich: enrich})
+	})
+}
+
+// enrichedRulesKey lists the indexes of the rules applied to a span when it started, so they are not
+// applied again when it ends. It is removed before the span is exported.
+const enrichedRulesKey = attribute.Key("scout.enrichment.rules")
+
+// enrichmentProcessor applies the enrichment rules to spans as they start, ahead of the other processors.
+type enrichmentProcessor struct {
+	rules []enrichmentRule
+}
+
+var _ sdktrace.SpanProcessor = enrichmentProcessor{}
+
+func (p enrichmentProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	var applied []int64
+	for i, rule := range p.rules {
+		if rule.match != nil && !rule.match(s) {
+			continue
+		}
+		rule.apply(s)
+		applied = append(applied, int64(i))
+	}
+	if len(applied) > 0 {
+		s.SetAttributes(enrichedRulesKey.Int64Slice(applied))
+	}
+}
+
+func (rule enrichmentRule) apply(s sdktrace.ReadWriteSpan) {
+	if len(rule.attrs) > 0 {
+		s.SetAttributes(rule.attrs...)
+	}
+	if rule.enrich != nil {
+		rule.enrich(s)
+	}
+}
+
+func (p enrichmentProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p enrichmentProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p enrichmentProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// enrichmentFilter applies the enrichment rules that did not match a span when it started to the ended
+// span, as its name and most of its attributes are only set by then.
+func enrichmentFilter(rules []enrichmentRule) spanFilter {
+	return func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		filtered := newFilteredSpan(s)
+		applied := map[int64]bool{}
+		filtered.attributes = mapAttributes(filtered.attributes, func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+			if kv.Key != enrichedRulesKey {
+				return kv, true
+			}
+			for _, i := range kv.Value.AsInt64Slice() {
+				applied[i] = true
+			}
+			return kv, false
+		})
+		for i, rule := range rules {
+			if applied[int64(i)] || rule.match != nil && !rule.match(filtered) {
+				continue
+			}
+			rule.apply(endedSpan{filteredSpan: filtered})
+		}
+		return filtered
+	}
+}
+
+// endedSpan lets the enrichers change the attributes, events and status of an ended span.
+type endedSpan struct {
+	*filteredSpan
+	noop.Span
+}
+
+var _ sdktrace.ReadWriteSpan = endedSpan{}
+
+func (s endedSpan) SpanContext() trace.SpanContext {
+	return s.filteredSpan.SpanContext()
+}
+
+func (s endedSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attributes = setAttributes(s.attributes, kv)
+}
+
+func (s endedSpan) AddEvent(name string, opts ...trace.EventOption) {
+	cfg := trace.NewEventConfig(opts...)
+	s.events = append(s.events[:len(s.events):len(s.events)], sdktrace.Event{Name: name, Attributes: cfg.Attributes(), Time: cfg.Timestamp()})
+}
+
+func (s endedSpan) SetStatus(code codes.Code, description string) {
+	if code != codes.Error {
+		description = ""
+	}
+	s.status = sdktrace.Status{Code: code, Description: description}
+}
+
+// setAttributes returns attrs with kv set, replacing the attributes with the same keys.
+func setAttributes(attrs []attribute.KeyValue, kv []attribute.KeyValue) []attribute.KeyValue {
+	set := make([]attribute.KeyValue, 0, len(attrs)+len(kv))
+	for _, attr := range attrs {
+		if !slices.ContainsFunc(kv, func(other attribute.KeyValue) bool { return other.Key == attr.Key }) {
+			set = append(set, attr)
+		}
+	}
+	return append(set, kv...)
+}
@@ -0,0 +1,86 @@
+package scout
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEnrichmentProcessor(t *testing.T) {
+	c := &config{}
+	WithSpanEnrichment(nil, attribute.String("region", "eu")).apply(c)
+	WithSpanEnrichment(MatchSpanName(regexp.MustCompile(`^/api/payments/`)), attribute.String("team", "payments")).apply(c)
+	WithSpanEnricher(MatchSpanAttribute(attribute.String("tenant", "acme")), func(span sdktrace.ReadWriteSpan) {
+		span.SetAttributes(attribute.Bool("tenant.enterprise", true))
+	}).apply(c)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(enrichmentProcessor{rules: c.enrichmentRules}),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+	tr := provider.Tracer("test")
+
+	_, span := tr.Start(context.Background(), "/api/payments/charge")
+	span.End()
+	_, span = tr.Start(context.Background(), "/api/users", trace.WithAttributes(attribute.String("tenant", "acme")))
+	span.End()
+
+	spans := recorder.Ended()
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("region", "eu"),
+		attribute.String("team", "payments"),
+		enrichedRulesKey.Int64Slice([]int64{0, 1}),
+	}, spans[0].Attributes())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("tenant", "acme"),
+		attribute.String("region", "eu"),
+		attribute.Bool("tenant.enterprise", true),
+		enrichedRulesKey.Int64Slice([]int64{0, 2}),
+	}, spans[1].Attributes())
+}
+
+func TestEnrichmentFilter(t *testing.T) {
+	c := &config{}
+	WithSpanEnrichment(nil, attribute.String("region", "eu")).apply(c)
+	WithSpanEnrichment(MatchSpanName(regexp.MustCompile(`^GET /api/payments/`)), attribute.String("team", "payments")).apply(c)
+	WithSpanEnricher(MatchSpanAttribute(attribute.String("tenant", "acme")), func(span sdktrace.ReadWriteSpan) {
+		span.SetAttributes(attribute.Bool("tenant.enterprise", true))
+		span.AddEvent("enterprise")
+		span.SetStatus(codes.Error, "enterprise")
+	}).apply(c)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(enrichmentProcessor{rules: c.enrichmentRules}),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	// named and tagged once the handler returns, as the middlewares do
+	_, span := provider.Tracer("test").Start(context.Background(), "scout.chi")
+	span.SetName("GET /api/payments/{id}")
+	span.SetAttributes(attribute.String("tenant", "acme"), attribute.String("region", "us"))
+	span.End()
+
+	filtered := enrichmentFilter(c.enrichmentRules)(recorder.Ended()[0])
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("region", "us"),
+		attribute.String("tenant", "acme"),
+		attribute.String("team", "payments"),
+		attribute.Bool("tenant.enterprise", true),
+	}, filtered.Attributes())
+	require.Len(t, filtered.Events(), 1)
+	assert.Equal(t, "enterprise", filtered.Events()[0].Name)
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "enterprise"}, filtered.Status())
+	assert.Empty(t, recorder.Ended()[0].Events(), "the recorded span is left unchanged")
+}
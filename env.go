@@ -0,0 +1,74 @@
+package scout
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Standard OpenTelemetry environment variables, honored when the equivalent option is not set.
+// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES are read when the resource is created, and take
+// precedence over the attributes detected from the build info but not over WithServiceName,
+// WithServiceVersion or WithEnvironment.
+const (
+	envOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOTLPHeaders  = "OTEL_EXPORTER_OTLP_HEADERS"
+	envOTLPTimeout  = "OTEL_EXPORTER_OTLP_TIMEOUT"
+)
+
+// otlpEndpoint returns the endpoint set with SetOtelEndpoint, or else OTEL_EXPORTER_OTLP_ENDPOINT,
+// or else OTLPDefaultEndpoint.
+func otlpEndpoint() string {
+	conf := loadConfig()
+	if !conf.otelEndpointSet {
+		if endpoint := strings.TrimSpace(os.Getenv(envOTLPEndpoint)); endpoint != "" {
+			return strings.TrimSuffix(endpoint, "/")
+		}
+	}
+	return conf.otelEndpoint
+}
+
+// otlpHeaders parses OTEL_EXPORTER_OTLP_HEADERS, a comma separated list of url encoded key=value pairs.
+func otlpHeaders() map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv(envOTLPHeaders), ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		key, err1 := url.PathUnescape(strings.TrimSpace(key))
+		value, err2 := url.PathUnescape(strings.TrimSpace(value))
+		if err1 != nil || err2 != nil || key == "" {
+			logger.Warnf("ignoring invalid %s entry %q", envOTLPHeaders, pair)
+			continue
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
+// otlpTimeout parses OTEL_EXPORTER_OTLP_TIMEOUT, in milliseconds. It returns 0 if it is not set.
+func otlpTimeout() time.Duration {
+	value := strings.TrimSpace(os.Getenv(envOTLPTimeout))
+	if value == "" {
+		return 0
+	}
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		logger.Warnf("ignoring invalid %s %q", envOTLPTimeout, value)
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// exportRequestTimeout returns the timeout of a single OTLP request, set by WithExportTimeout
+// or OTEL_EXPORTER_OTLP_TIMEOUT. It returns 0 if neither is set.
+func exportRequestTimeout() time.Duration {
+	conf := loadConfig()
+	if conf.exportTimeout > 0 {
+		return conf.exportTimeout
+	}
+	return otlpTimeout()
+}
@@ -0,0 +1,23 @@
+package scout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOTLPEnvironment(t *testing.T) {
+	useConfig(t, &config{otelEndpoint: OTLPDefaultEndpoint})
+
+	t.Setenv(envOTLPEndpoint, "http://collector:4318/")
+	t.Setenv(envOTLPHeaders, "x-api-key=abc%20def, x-tenant = acme,invalid")
+	t.Setenv(envOTLPTimeout, "2500")
+
+	assert.Equal(t, "http://collector:4318", otlpEndpoint())
+	assert.Equal(t, map[string]string{"x-api-key": "abc def", "x-tenant": "acme"}, otlpHeaders())
+	assert.Equal(t, 2500*time.Millisecond, otlpTimeout())
+
+	SetOtelEndpoint("https://otel.example.com")
+	assert.Equal(t, "https://otel.example.com", otlpEndpoint())
+}
@@ -0,0 +1,140 @@
+package scout
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	rtrace "runtime/trace"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ProfileExecutionTrace ProfileType = "trace"
+
+// profileFormatGoTrace is a gzipped runtime/trace execution trace, readable with go tool trace
+const profileFormatGoTrace = "go-trace"
+
+// executionTraceWindow is the length of each execution trace segment.
+const executionTraceWindow = 10 * time.Second
+
+// WithSlowRequestTracing continuously records runtime/trace execution traces in 10 second segments and
+// uploads a segment when a request span exceeding threshold ended during it, to diagnose latency
+// caused by the scheduler or the garbage collector. The segment is recorded as a profile event on a
+// child of the slowest request span, and each request span is annotated as a task in the trace.
+// Execution tracing costs a few percent of CPU, and segments are skipped while the application
+// is running its own execution trace.
+func WithSlowRequestTracing(threshold time.Duration) Option {
+	return option(func(conf *config) {
+		conf.slowRequestThreshold = threshold
+	})
+}
+
+// executionTracer records execution trace segments and the slow request spans that ended in them.
+type executionTracer struct {
+	threshold time.Duration
+	tasks     sync.Map // trace.SpanID -> *rtrace.Task
+
+	mu      sync.Mutex
+	buf     *bytes.Buffer
+	slowest sdktrace.ReadOnlySpan
+}
+
+var _ sdktrace.SpanProcessor = (*executionTracer)(nil)
+
+func newExecutionTracer(threshold time.Duration) *executionTracer {
+	return &executionTracer{threshold: threshold}
+}
+
+// isRequestSpan reports whether the span is the local root of a trace, such as the span of a request.
+func isRequestSpan(parent trace.SpanContext) bool {
+	return !parent.IsValid() || parent.IsRemote()
+}
+
+func (e *executionTracer) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if !rtrace.IsEnabled() || !isRequestSpan(s.Parent()) {
+		return
+	}
+	ctx, task := rtrace.NewTask(parent, s.Name())
+	rtrace.Log(ctx, RequestIDAttribute, s.SpanContext().TraceID().String())
+	e.tasks.Store(s.SpanContext().SpanID(), task)
+}
+
+func (e *executionTracer) OnEnd(s sdktrace.ReadOnlySpan) {
+	if task, ok := e.tasks.LoadAndDelete(s.SpanContext().SpanID()); ok {
+		task.(*rtrace.Task).End()
+	}
+	if !isRequestSpan(s.Parent()) || s.EndTime().Sub(s.StartTime()) < e.threshold {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.buf == nil {
+		return
+	}
+	if e.slowest == nil || s.EndTime().Sub(s.StartTime()) > e.slowest.EndTime().Sub(e.slowest.StartTime()) {
+		e.slowest = s
+	}
+}
+
+func (e *executionTracer) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *executionTracer) Shutdown(context.Context) error {
+	return nil
+}
+
+// rotate ends the current segment, uploading it if a slow request ended during it, and starts the next.
+func (e *executionTracer) rotate(ctx context.Context) {
+	e.stop()
+	if ctx.Err() != nil {
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := rtrace.Start(buf); err != nil {
+		logger.Debugf("skipping execution trace: %s", err)
+		return
+	}
+	e.mu.Lock()
+	e.buf = buf
+	e.mu.Unlock()
+}
+
+// stop ends the current segment, uploading it if a slow request ended during it.
+func (e *executionTracer) stop() {
+	e.mu.Lock()
+	buf, slowest := e.buf, e.slowest
+	e.buf, e.slowest = nil, nil
+	e.mu.Unlock()
+	if buf == nil {
+		return
+	}
+	rtrace.Stop()
+	if slowest == nil {
+		return
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(buf.Bytes()); err != nil {
+		logger.Errorf("failed to compress execution trace: %s", err)
+		return
+	}
+	if err := gz.Close(); err != nil {
+		logger.Errorf("failed to compress execution trace: %s", err)
+		return
+	}
+	ctx := trace.ContextWithSpanContext(context.Background(), slowest.SpanContext())
+	recordProfileData(ctx, ProfileExecutionTrace, profileFormatGoTrace, compressed.Bytes())
+}
+
+func startExecutionTracer(o *OTLP) {
+	if o == nil || o.executionTracer == nil {
+		return
+	}
+	onStopWorkers(o.executionTracer.stop)
+	startPeriodic(executionTraceWindow, o.executionTracer.rotate)
+}
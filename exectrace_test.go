@@ -0,0 +1,35 @@
+package scout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExecutionTracer(t *testing.T) {
+	e := newExecutionTracer(time.Millisecond)
+	recorder := tracetest.NewSpanRecorder()
+	prev := loadTracer()
+	storeTracer(newTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(e), sdktrace.WithSpanProcessor(recorder))))
+	t.Cleanup(func() { storeTracer(prev) })
+
+	e.rotate(context.Background())
+	fast, _ := StartTrace(context.Background(), "fast")
+	EndTrace(fast)
+	slow, _ := StartTrace(context.Background(), "slow")
+	time.Sleep(2 * time.Millisecond)
+	EndTrace(slow)
+	e.stop()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	attachment := spans[2]
+	assert.Equal(t, slow.SpanContext().SpanID(), attachment.Parent().SpanID())
+	require.Len(t, attachment.Events(), 1)
+	assert.Contains(t, attachment.Events()[0].Attributes, String(ProfileFormatAttribute, profileFormatGoTrace))
+}
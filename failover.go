@@ -0,0 +1,131 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// failoverProbeInterval is how often the primary endpoint is retried after failing over.
+const failoverProbeInterval = 30 * time.Second
+
+// WithFailoverEndpoints sets OTLP endpoints to fail over to, in order of priority, when the primary
+// endpoint keeps failing. Export switches to the next endpoint once an upload to the current one fails
+// after its retries are exhausted, and fails back once the primary accepts spans again. Spans and logs
+// fail over independently.
+func WithFailoverEndpoints(endpoints ...string) Option {
+	return option(func(conf *config) {
+		conf.failoverEndpoints = endpoints
+	})
+}
+
+// failover sends to the first healthy endpoint of a prioritized list, failing back to the primary
+// endpoint once it recovers.
+type failover struct {
+	endpoints []string
+
+	mu        sync.Mutex
+	active    int
+	lastProbe time.Time
+	now       func() time.Time
+}
+
+func newFailover(endpoints []string) failover {
+	return failover{endpoints: endpoints, now: time.Now}
+}
+
+// send calls send with the index of the active endpoint, failing over to the next endpoint while it
+// fails, and returns the last error. The primary endpoint is probed again every failoverProbeInterval.
+func (f *failover) send(send func(i int) error) error {
+	active := f.current()
+	if active > 0 && f.shouldProbe() {
+		if err := send(0); err == nil {
+			logger.Infof("otlp endpoint %s recovered, failing back", redactEndpoint(f.endpoints[0]))
+			f.setActive(0)
+			return nil
+		}
+	}
+	var err error
+	for i := active; i < len(f.endpoints); i++ {
+		if err = send(i); err == nil {
+			f.setActive(i)
+			return nil
+		}
+		if i+1 < len(f.endpoints) {
+			logger.Warnf("otlp endpoint %s failed, failing over to %s: %s", redactEndpoint(f.endpoints[i]), redactEndpoint(f.endpoints[i+1]), err)
+		}
+	}
+	return err
+}
+
+func (f *failover) current() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+func (f *failover) setActive(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active == 0 && i > 0 {
+		f.lastProbe = f.now()
+	}
+	f.active = i
+}
+
+// shouldProbe reports whether it is time to retry the primary endpoint.
+func (f *failover) shouldProbe() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.now().Sub(f.lastProbe) < failoverProbeInterval {
+		return false
+	}
+	f.lastProbe = f.now()
+	return true
+}
+
+// failoverClient uploads spans to the first healthy endpoint of a prioritized list.
+type failoverClient struct {
+	clients []otlptrace.Client
+	failover
+}
+
+var _ otlptrace.Client = (*failoverClient)(nil)
+
+func newFailoverClient(endpoints []string) (*failoverClient, error) {
+	c := &failoverClient{failover: newFailover(endpoints)}
+	for _, endpoint := range endpoints {
+		client, err := newTraceClient(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		c.clients = append(c.clients, client)
+	}
+	return c, nil
+}
+
+func (c *failoverClient) Start(ctx context.Context) error {
+	var errs []error
+	for _, client := range c.clients {
+		errs = append(errs, client.Start(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *failoverClient) Stop(ctx context.Context) error {
+	var errs []error
+	for _, client := range c.clients {
+		errs = append(errs, client.Stop(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *failoverClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	return c.send(func(i int) error {
+		return c.clients[i].UploadTraces(ctx, protoSpans)
+	})
+}
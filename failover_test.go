@@ -0,0 +1,54 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+type stubClient struct {
+	err     error
+	uploads int
+}
+
+func (c *stubClient) Start(context.Context) error { return nil }
+func (c *stubClient) Stop(context.Context) error  { return nil }
+
+func (c *stubClient) UploadTraces(context.Context, []*tracepb.ResourceSpans) error {
+	c.uploads++
+	return c.err
+}
+
+func TestFailoverClient(t *testing.T) {
+	primary, secondary := &stubClient{err: errors.New("unavailable")}, &stubClient{}
+	now := time.Now()
+	c := &failoverClient{
+		clients:  []otlptrace.Client{primary, secondary},
+		failover: failover{endpoints: []string{"https://primary", "https://secondary"}, now: func() time.Time { return now }},
+	}
+	ctx := context.Background()
+
+	require.NoError(t, c.UploadTraces(ctx, nil))
+	assert.Equal(t, 1, c.current())
+
+	// the primary is not probed again until the probe interval elapses
+	require.NoError(t, c.UploadTraces(ctx, nil))
+	assert.Equal(t, 1, primary.uploads)
+	assert.Equal(t, 2, secondary.uploads)
+
+	primary.err = nil
+	now = now.Add(failoverProbeInterval)
+	require.NoError(t, c.UploadTraces(ctx, nil))
+	assert.Equal(t, 0, c.current())
+	assert.Equal(t, 2, primary.uploads)
+	assert.Equal(t, 2, secondary.uploads)
+
+	primary.err, secondary.err = errors.New("unavailable"), errors.New("unavailable")
+	assert.Error(t, c.UploadTraces(ctx, nil))
+}
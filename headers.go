@@ -0,0 +1,91 @@
+package scout
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultRedactedHeaders are headers that commonly carry credentials.
+var defaultRedactedHeaders = []string{
+	"authorization", "proxy-authorization", "cookie", "set-cookie",
+	"x-api-key", "api-key", "x-auth-token", "x-csrf-token", "x-xsrf-token",
+}
+
+// WithRequestHeaders records the values of the given request headers on the spans of middleware,
+// as http.request.header.<name> attributes. Values of credential headers are redacted, see WithRedactedHeaders.
+func WithRequestHeaders(headers ...string) Option {
+	return runtimeOption(func(conf *config) {
+		for _, header := range headers {
+			conf.requestHeaders = append(conf.requestHeaders, http.CanonicalHeaderKey(header))
+		}
+	})
+}
+
+// WithResponseHeaders records the values of the given response headers on the spans of middleware,
+// as http.response.header.<name> attributes, such as cache status or rate limit headers.
+// Values of credential headers are redacted, see WithRedactedHeaders.
+func WithResponseHeaders(headers ...string) Option {
+	return runtimeOption(func(conf *config) {
+		for _, header := range headers {
+			conf.responseHeaders = append(conf.responseHeaders, http.CanonicalHeaderKey(header))
+		}
+	})
+}
+
+// WithRedactedHeaders replaces the headers whose values are redacted when captured. By default these are
+// Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-Api-Key, Api-Key, X-Auth-Token,
+// X-Csrf-Token and X-Xsrf-Token. Headers are matched case-insensitively.
+func WithRedactedHeaders(headers ...string) Option {
+	return runtimeOption(func(conf *config) {
+		conf.redactedHeaders = make([]string, len(headers))
+		for i, header := range headers {
+			conf.redactedHeaders[i] = strings.ToLower(header)
+		}
+	})
+}
+
+func isRedactedHeader(header string) bool {
+	conf := loadConfig()
+	redacted := defaultRedactedHeaders
+	if conf.redactedHeaders != nil {
+		redacted = conf.redactedHeaders
+	}
+	header = strings.ToLower(header)
+	for _, h := range redacted {
+		if header == h {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestHeaderAttributes returns attributes holding the request headers set by WithRequestHeaders,
+// with the values of credential headers redacted.
+func RequestHeaderAttributes(h http.Header) []attribute.KeyValue {
+	conf := loadConfig()
+	return headerAttributes("http.request.header.", conf.requestHeaders, h)
+}
+
+// ResponseHeaderAttributes returns attributes holding the response headers set by WithResponseHeaders,
+// with the values of credential headers redacted.
+func ResponseHeaderAttributes(h http.Header) []attribute.KeyValue {
+	conf := loadConfig()
+	return headerAttributes("http.response.header.", conf.responseHeaders, h)
+}
+
+func headerAttributes(prefix string, names []string, h http.Header) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, name := range names {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if isRedactedHeader(name) {
+			values = []string{RedactedValue}
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+strings.ToLower(name), values))
+	}
+	return attrs
+}
@@ -0,0 +1,49 @@
+package scout
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRequestHeaderAttributes(t *testing.T) {
+	useConfig(t, &config{})
+
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	h.Set("Authorization", "Bearer secret")
+	h.Add("X-Forwarded-For", "10.0.0.1")
+	h.Add("X-Forwarded-For", "10.0.0.2")
+
+	updateConfig(WithRequestHeaders("content-type", "Authorization", "x-forwarded-for", "X-Missing").apply)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("http.request.header.content-type", []string{"application/json"}),
+		attribute.StringSlice("http.request.header.authorization", []string{RedactedValue}),
+		attribute.StringSlice("http.request.header.x-forwarded-for", []string{"10.0.0.1", "10.0.0.2"}),
+	}, RequestHeaderAttributes(h))
+
+	updateConfig(WithRedactedHeaders("X-Forwarded-For").apply)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("http.request.header.content-type", []string{"application/json"}),
+		attribute.StringSlice("http.request.header.authorization", []string{"Bearer secret"}),
+		attribute.StringSlice("http.request.header.x-forwarded-for", []string{RedactedValue}),
+	}, RequestHeaderAttributes(h))
+}
+
+func TestResponseHeaderAttributes(t *testing.T) {
+	useConfig(t, &config{})
+
+	h := http.Header{}
+	h.Set("X-Cache", "HIT")
+	h.Set("Set-Cookie", "session=secret")
+	h.Set("X-Ratelimit-Remaining", "0")
+
+	updateConfig(WithResponseHeaders("x-cache", "set-cookie").apply)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("http.response.header.x-cache", []string{"HIT"}),
+		attribute.StringSlice("http.response.header.set-cookie", []string{RedactedValue}),
+	}, ResponseHeaderAttributes(h))
+	assert.Empty(t, RequestHeaderAttributes(h))
+}
@@ -0,0 +1,51 @@
+package scout
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const HeartbeatUptimeAttribute = "scout.uptime"
+
+var (
+	// instanceID identifies this process in heartbeats
+	instanceID   = uuid.NewString()
+	processStart = time.Now()
+)
+
+// WithHeartbeat emits a heartbeat span every interval while Scout is running, so the backend can tell
+// a service that is down from one that is up but idle. Heartbeats carry the instance ID and uptime
+// of the process and are never sampled out.
+func WithHeartbeat(interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.heartbeatInterval = interval
+	})
+}
+
+// InstanceID returns the ID identifying this process in heartbeats.
+func InstanceID() string {
+	return instanceID
+}
+
+func startHeartbeat() {
+	conf := loadConfig()
+	if conf.heartbeatInterval <= 0 {
+		return
+	}
+	startPeriodic(conf.heartbeatInterval, func(context.Context) {
+		recordHeartbeat()
+	})
+}
+
+func recordHeartbeat() {
+	span, _ := startInternalTrace(context.Background(), ScopedKey("heartbeat", ptr.String("-")),
+		semconv.ServiceInstanceIDKey.String(instanceID),
+		attribute.Float64(HeartbeatUptimeAttribute, time.Since(processStart).Seconds()),
+	)
+	EndTrace(span)
+}
@@ -0,0 +1,49 @@
+package scout
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeartbeatIsNeverSampledOut(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := loadTracer()
+	storeTracer(newTracer(sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampler{traceIDUpperBounds: map[trace.SpanKind]uint64{trace.SpanKindUnspecified: 0}}),
+		sdktrace.WithSpanProcessor(recorder),
+	)))
+	t.Cleanup(func() { storeTracer(prev) })
+
+	span, _ := StartTrace(context.Background(), "sampled out")
+	EndTrace(span)
+	recordHeartbeat()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.ServiceInstanceIDKey.String(InstanceID()))
+	assert.Contains(t, spans[0].Attributes(), attribute.String(TraceTypeAttribute, string(TraceTypeScoutInternal)))
+}
+
+func TestPeriodicWorkers(t *testing.T) {
+	var calls atomic.Int32
+	startWorkers()
+	startPeriodic(time.Millisecond, func(context.Context) {
+		calls.Add(1)
+	})
+	require.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	stopWorkers()
+
+	stopped := calls.Load()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, stopped, calls.Load())
+}
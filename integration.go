@@ -0,0 +1,63 @@
+package scout
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// envDisabledIntegrations lists the integrations disabled when the process starts, separated by commas,
+// such as SCOUT_DISABLED_INTEGRATIONS=gorm,logrus.
+const envDisabledIntegrations = "SCOUT_DISABLED_INTEGRATIONS"
+
+// Names of the integrations that can be disabled with DisableIntegration.
+const (
+	IntegrationApex       = "apex"
+	IntegrationChi        = "chi"
+	IntegrationEcho       = "echo"
+	IntegrationFiber      = "fiber"
+	IntegrationGin        = "gin"
+	IntegrationGorillaMux = "gorillamux"
+	IntegrationGoKit      = "gokit"
+	IntegrationGorm       = "gorm"
+	IntegrationGraphQL    = "graphql"
+	IntegrationKlog       = "klog"
+	IntegrationLogrus     = "logrus"
+	IntegrationLogWriter  = "logwriter"
+	IntegrationSlog       = "slog"
+	IntegrationZap        = "zap"
+	IntegrationZerolog    = "zerolog"
+	IntegrationExec       = "exec"
+	IntegrationMigrate    = "migrate"
+	IntegrationCache      = "cache"
+)
+
+// disabledIntegrations holds the names of the disabled integrations.
+var disabledIntegrations sync.Map
+
+func loadDisabledIntegrations() {
+	for _, name := range strings.Split(os.Getenv(envDisabledIntegrations), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			DisableIntegration(name)
+		}
+	}
+}
+
+// DisableIntegration turns the integration called name, such as IntegrationGorm, into a no-op until it
+// is enabled again, for example when it misbehaves in production. Integrations can also be disabled
+// on start with the SCOUT_DISABLED_INTEGRATIONS environment variable. Names are case-insensitive.
+func DisableIntegration(name string) {
+	disabledIntegrations.Store(strings.ToLower(name), struct{}{})
+}
+
+// EnableIntegration enables an integration disabled with DisableIntegration.
+func EnableIntegration(name string) {
+	disabledIntegrations.Delete(strings.ToLower(name))
+}
+
+// IntegrationEnabled reports whether the integration called name is enabled. Integrations check it
+// whenever they would record telemetry.
+func IntegrationEnabled(name string) bool {
+	_, disabled := disabledIntegrations.Load(strings.ToLower(name))
+	return !disabled
+}
@@ -0,0 +1,28 @@
+package scout
+
+import (
+	"testing"
+)
+
+func TestDisableIntegration(t *testing.T) {
+	t.Setenv(envDisabledIntegrations, "Gorm, logrus,")
+	loadDisabledIntegrations()
+	defer EnableIntegration(IntegrationGorm)
+	defer EnableIntegration(IntegrationLogrus)
+
+	if IntegrationEnabled(IntegrationGorm) || IntegrationEnabled(IntegrationLogrus) {
+		t.Fatalf("[IntegrationEnabled] expected the integrations from %s to be disabled", envDisabledIntegrations)
+	}
+	if !IntegrationEnabled(IntegrationGin) {
+		t.Fatalf("[IntegrationEnabled] expected other integrations to stay enabled")
+	}
+
+	DisableIntegration(IntegrationGin)
+	if IntegrationEnabled("GIN") {
+		t.Fatalf("[DisableIntegration] expected gin to be disabled")
+	}
+	EnableIntegration(IntegrationGin)
+	if !IntegrationEnabled(IntegrationGin) {
+		t.Fatalf("[EnableIntegration] expected gin to be enabled again")
+	}
+}
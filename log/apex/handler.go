@@ -0,0 +1,107 @@
+// Package apex provides an apex/log handler that ships logs to Scout, for services logging with apex/log
+// rather than logrus.
+package apex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apexlog "github.com/apex/log"
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Option applies a configuration to the given handler.
+type Option func(h *Handler)
+
+// WithLevel sets the minimum level of the logs exported to Scout.
+//
+// The default is apexlog.WarnLevel.
+func WithLevel(level apexlog.Level) Option {
+	return func(h *Handler) {
+		h.level = level
+	}
+}
+
+// WithHandler also passes the entries to next, such as a cli or json handler, so they are still written
+// out locally.
+func WithHandler(next apexlog.Handler) Option {
+	return func(h *Handler) {
+		h.next = next
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(h *Handler) {
+		h.mode = mode
+	}
+}
+
+// Handler is an apex/log handler that exports entries to Scout, with their fields as attributes. Entries
+// have no context, so a context.Context field exports the entry within the trace and the Scout request
+// of the context:
+//
+//	apexlog.SetHandler(apex.NewHandler(apex.WithHandler(cli.Default)))
+//	apexlog.WithField("ctx", ctx).WithError(err).Error("charge failed")
+type Handler struct {
+	level            apexlog.Level
+	errorStatusLevel apexlog.Level
+	mode             scout.LogMode
+	next             apexlog.Handler
+}
+
+var _ apexlog.Handler = (*Handler)(nil)
+
+// NewHandler returns an apex/log handler.
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{
+		level:            apexlog.WarnLevel,
+		errorStatusLevel: apexlog.ErrorLevel,
+	}
+
+	for _, fn := range opts {
+		fn(h)
+	}
+
+	return h
+}
+
+// HandleLog exports an entry.
+func (h *Handler) HandleLog(e *apexlog.Entry) error {
+	var err error
+	if h.next != nil {
+		err = h.next.HandleLog(e)
+	}
+
+	if e.Level < h.level || !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationApex) {
+		return err
+	}
+
+	ctx, attrs := fields(e.Fields)
+	scout.RecordLog(ctx, scout.LogRecord{
+		Time:       e.Timestamp,
+		Severity:   strings.ToUpper(e.Level.String()),
+		Message:    e.Message,
+		Attributes: attrs,
+		Error:      e.Level >= h.errorStatusLevel,
+		Mode:       h.mode,
+	})
+	return err
+}
+
+// fields returns the context among the fields, and the other fields as attributes sorted by name.
+func fields(f apexlog.Fields) (context.Context, []attribute.KeyValue) {
+	ctx := context.TODO()
+	attrs := make([]attribute.KeyValue, 0, len(f))
+	for _, name := range f.Names() {
+		v := f[name]
+		if c, ok := v.(context.Context); ok {
+			ctx = c
+			continue
+		}
+		attrs = append(attrs, attribute.String(name, fmt.Sprintf("%+v", v)))
+	}
+	return ctx, attrs
+}
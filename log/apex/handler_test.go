@@ -0,0 +1,37 @@
+package apex
+
+import (
+	"context"
+	"testing"
+
+	apexlog "github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ctxKey struct{}
+
+func TestFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	got, attrs := fields(apexlog.Fields{"ctx": ctx, "error": "declined", "amount": 12})
+
+	assert.Equal(t, ctx, got)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("amount", "12"),
+		attribute.String("error", "declined"),
+	}, attrs)
+
+	got, attrs = fields(nil)
+	assert.Equal(t, context.TODO(), got)
+	assert.Empty(t, attrs)
+}
+
+func TestNextHandler(t *testing.T) {
+	next := memory.New()
+	logger := &apexlog.Logger{Handler: NewHandler(WithHandler(next)), Level: apexlog.DebugLevel}
+
+	logger.WithField("status", 200).Info("served")
+	assert.Len(t, next.Entries, 1)
+	assert.Equal(t, "served", next.Entries[0].Message)
+}
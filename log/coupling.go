@@ -0,0 +1,89 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxCoupledTraces bounds the unsampled traces buffering logs, further traces are not buffered.
+	maxCoupledTraces = 1000
+	// maxCoupledRecords bounds the logs buffered per trace, further logs are dropped.
+	maxCoupledRecords = 100
+)
+
+// traceCoupling buffers the logs of unsampled traces until an error is logged in the trace.
+type traceCoupling struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	traces    map[trace.TraceID]*coupledTrace
+	lastPrune time.Time
+}
+
+type coupledTrace struct {
+	started time.Time
+	records []record
+	// errored is set once an error was logged, so the remaining logs of the trace are exported
+	errored bool
+}
+
+func newTraceCoupling(window time.Duration) *traceCoupling {
+	return &traceCoupling{
+		window:    window,
+		traces:    map[trace.TraceID]*coupledTrace{},
+		lastPrune: time.Now(),
+	}
+}
+
+// add returns the records to export for a log written in the trace of psc:
+// the record itself if the trace was sampled or has errored, along with
+// the records buffered for the trace if the log is an error.
+func (c *traceCoupling) add(psc trace.SpanContext, r record, isError bool) []record {
+	if psc.IsSampled() {
+		return []record{r}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.prune(now)
+
+	t, ok := c.traces[psc.TraceID()]
+	if !ok {
+		if len(c.traces) >= maxCoupledTraces {
+			return nil
+		}
+		t = &coupledTrace{started: now}
+		c.traces[psc.TraceID()] = t
+	}
+	if t.errored {
+		return []record{r}
+	}
+	if isError {
+		records := append(t.records, r)
+		t.records = nil
+		t.errored = true
+		return records
+	}
+	if len(t.records) < maxCoupledRecords {
+		t.records = append(t.records, r)
+	}
+	return nil
+}
+
+// prune drops the traces buffered for longer than the window, checking at most once per window.
+func (c *traceCoupling) prune(now time.Time) {
+	if now.Sub(c.lastPrune) < c.window {
+		return
+	}
+	c.lastPrune = now
+	for id, t := range c.traces {
+		if now.Sub(t.started) >= c.window {
+			delete(c.traces, id)
+		}
+	}
+}
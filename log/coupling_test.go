@@ -0,0 +1,31 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceCoupling(t *testing.T) {
+	c := newTraceCoupling(time.Minute)
+	sampled := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	unsampled := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{2}, SpanID: trace.SpanID{1}})
+
+	assert.Equal(t, []record{{message: "sampled"}}, c.add(sampled, record{message: "sampled"}, false))
+
+	assert.Empty(t, c.add(unsampled, record{message: "first"}, false))
+	assert.Empty(t, c.add(unsampled, record{message: "second"}, false))
+	assert.Equal(t, []record{{message: "first"}, {message: "second"}, {message: "error"}}, c.add(unsampled, record{message: "error"}, true))
+	assert.Equal(t, []record{{message: "after"}}, c.add(unsampled, record{message: "after"}, false))
+}
+
+func TestTraceCouplingExpires(t *testing.T) {
+	c := newTraceCoupling(time.Millisecond)
+	unsampled := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{2}, SpanID: trace.SpanID{1}})
+
+	assert.Empty(t, c.add(unsampled, record{message: "expired"}, false))
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(t, []record{{message: "error"}}, c.add(unsampled, record{message: "error"}, true))
+}
@@ -0,0 +1,187 @@
+// Package gokit provides a go-kit logger that ships logs to Scout, for services logging with go-kit/log
+// rather than logrus.
+package gokit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Keys of the keyvals read by Logger, as set by go-kit's log.DefaultCaller and log.DefaultTimestamp.
+const (
+	MessageKey   = "msg"
+	CallerKey    = "caller"
+	TimestampKey = "ts"
+)
+
+// levels ranks the go-kit levels.
+var levels = map[string]int{"debug": 0, "info": 1, "warn": 2, "error": 3}
+
+// Option applies a configuration to the given logger.
+type Option func(l *Logger)
+
+// WithLevel sets the minimum level of the logs exported to Scout.
+//
+// The default is level.WarnValue().
+func WithLevel(lvl level.Value) Option {
+	return func(l *Logger) {
+		l.level = levels[lvl.String()]
+	}
+}
+
+// WithLogger also passes the logs to next, such as a log.NewLogfmtLogger, so they are still written out
+// locally.
+func WithLogger(next log.Logger) Option {
+	return func(l *Logger) {
+		l.next = next
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(l *Logger) {
+		l.mode = mode
+	}
+}
+
+// Logger is a go-kit logger that exports logs to Scout. The level of a log is read from the keyval added
+// by the level package, and logs without a level are exported as information. A context.Context value
+// in the keyvals exports the log within the trace and the Scout request of the context:
+//
+//	logger := gokit.NewLogger(gokit.WithLogger(log.NewLogfmtLogger(os.Stderr)))
+//	logger = log.With(logger, "ts", log.DefaultTimestampUTC, "caller", log.DefaultCaller)
+//	level.Error(logger).Log("msg", "charge failed", "err", err, "ctx", ctx)
+type Logger struct {
+	level            int
+	errorStatusLevel int
+	mode             scout.LogMode
+	next             log.Logger
+}
+
+var _ log.Logger = (*Logger)(nil)
+
+// NewLogger returns a go-kit logger.
+func NewLogger(opts ...Option) *Logger {
+	l := &Logger{
+		level:            levels["warn"],
+		errorStatusLevel: levels["error"],
+	}
+
+	for _, fn := range opts {
+		fn(l)
+	}
+
+	return l
+}
+
+// Log exports a log made of alternating keys and values.
+func (l *Logger) Log(keyvals ...interface{}) error {
+	var err error
+	if l.next != nil {
+		err = l.next.Log(keyvals...)
+	}
+
+	if !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationGoKit) {
+		return err
+	}
+
+	r := newRecord(keyvals)
+	if levels[r.severity] < l.level {
+		return err
+	}
+	scout.RecordLog(r.ctx, scout.LogRecord{
+		Time:       r.time,
+		Severity:   strings.ToUpper(r.severity),
+		Message:    r.message,
+		Attributes: r.attrs,
+		Error:      levels[r.severity] >= l.errorStatusLevel,
+		Mode:       l.mode,
+	})
+	return err
+}
+
+// record holds what is exported of a log.
+type record struct {
+	ctx      context.Context
+	time     time.Time
+	severity string
+	message  string
+	attrs    []attribute.KeyValue
+}
+
+func newRecord(keyvals []interface{}) record {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, log.ErrMissingValue)
+	}
+	r := record{ctx: context.TODO(), severity: "info"}
+	for i := 0; i < len(keyvals); i += 2 {
+		k, v := keyvals[i], keyvals[i+1]
+		if k == level.Key() {
+			if lvl, ok := v.(level.Value); ok {
+				r.severity = lvl.String()
+				continue
+			}
+		}
+		if ctx, ok := v.(context.Context); ok {
+			r.ctx = ctx
+			continue
+		}
+		key := fmt.Sprint(k)
+		switch key {
+		case MessageKey:
+			r.message = fmt.Sprint(v)
+			continue
+		case TimestampKey:
+			if t, ok := timestamp(v); ok {
+				r.time = t
+				continue
+			}
+		case CallerKey:
+			if caller, ok := v.(string); ok {
+				r.attrs = append(r.attrs, callerAttributes(caller)...)
+				continue
+			}
+		}
+		r.attrs = append(r.attrs, attribute.String(key, fmt.Sprintf("%+v", v)))
+	}
+	return r
+}
+
+// timestamp returns the time of a ts value, a time.Time or a time formatted as RFC 3339 like
+// log.DefaultTimestamp.
+func timestamp(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case fmt.Stringer:
+		t, err := time.Parse(time.RFC3339Nano, v.String())
+		return t, err == nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// callerAttributes returns the code attributes of a file:line caller, as set by log.Caller.
+func callerAttributes(caller string) []attribute.KeyValue {
+	i := strings.LastIndexByte(caller, ':')
+	if i < 0 {
+		return []attribute.KeyValue{semconv.CodeFilepathKey.String(caller)}
+	}
+	file, line := caller[:i], caller[i+1:]
+	n, err := strconv.Atoi(line)
+	if err != nil {
+		return []attribute.KeyValue{semconv.CodeFilepathKey.String(caller)}
+	}
+	return []attribute.KeyValue{semconv.CodeFilepathKey.String(file), semconv.CodeLineNumberKey.Int(n)}
+}
@@ -0,0 +1,58 @@
+package gokit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ctxKey struct{}
+
+func TestNewRecord(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var r record
+	var logger log.Logger = log.LoggerFunc(func(keyvals ...interface{}) error {
+		r = newRecord(keyvals)
+		return nil
+	})
+	logger = log.With(logger, "ts", log.TimestampFormat(func() time.Time { return ts }, time.RFC3339Nano), "caller", "charge.go:42")
+	_ = level.Error(logger).Log("msg", "charge failed", "err", errors.New("declined"), "ctx", ctx, "amount", 12, "dangling")
+
+	assert.Equal(t, ctx, r.ctx)
+	assert.Equal(t, ts, r.time)
+	assert.Equal(t, "error", r.severity)
+	assert.Equal(t, "charge failed", r.message)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("code.filepath", "charge.go"),
+		attribute.Int("code.lineno", 42),
+		attribute.String("err", "declined"),
+		attribute.String("amount", "12"),
+		attribute.String("dangling", log.ErrMissingValue.Error()),
+	}, r.attrs)
+}
+
+func TestNewRecordDefaults(t *testing.T) {
+	r := newRecord([]interface{}{"msg", "started"})
+	assert.Equal(t, context.TODO(), r.ctx)
+	assert.Equal(t, "info", r.severity)
+	assert.True(t, r.time.IsZero())
+	assert.Empty(t, r.attrs)
+}
+
+func TestNextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogger(log.NewLogfmtLogger(&buf)), WithLevel(level.ErrorValue()))
+	assert.Equal(t, levels["error"], logger.level)
+
+	assert.NoError(t, level.Info(logger).Log("msg", "served", "status", 200))
+	assert.Equal(t, "level=info msg=served status=200\n", buf.String())
+}
@@ -0,0 +1,207 @@
+// Package klog provides a logr sink that ships logs to Scout, for Kubernetes controllers and operators
+// logging with klog/v2 or controller-runtime rather than logrus.
+package klog
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoggerNameKey is the attribute holding the name of the logger, the names given to WithName joined
+// with dots.
+const LoggerNameKey = "logger"
+
+// Option applies a configuration to the given sink.
+type Option func(s *Sink)
+
+// WithVerbosity exports the info logs up to verbosity level v, such as klog.V(2).Info. Error logs are
+// always exported.
+//
+// The default is not to export info logs.
+func WithVerbosity(v int) Option {
+	return func(s *Sink) {
+		s.verbosity = v
+	}
+}
+
+// WithSink also passes the logs to next, such as the sink of klog.NewKlogr or textlogger, so they are
+// still written out locally.
+func WithSink(next logr.LogSink) Option {
+	return func(s *Sink) {
+		s.next = next
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(s *Sink) {
+		s.mode = mode
+	}
+}
+
+// Sink is a logr sink that exports logs to Scout, with their key/values as attributes. Errors are also
+// recorded on the span of the context with scout.RecordSpanError. A context.Context value exports the log
+// within the trace and the Scout request of the context:
+//
+//	klog.SetLogger(logr.New(scoutklog.NewSink(scoutklog.WithSink(klog.NewKlogr().GetSink()))))
+//	klog.FromContext(ctx).Error(err, "reconcile failed", "ctx", ctx, "pod", klog.KObj(pod))
+type Sink struct {
+	verbosity int
+	next      logr.LogSink
+	name      string
+	values    []any
+	callDepth int
+	mode      scout.LogMode
+	// emit exports a log and records its error
+	emit func(ctx context.Context, r scout.LogRecord, err error)
+}
+
+var (
+	_ logr.LogSink          = (*Sink)(nil)
+	_ logr.CallDepthLogSink = (*Sink)(nil)
+)
+
+// NewSink returns a logr sink.
+func NewSink(opts ...Option) *Sink {
+	s := &Sink{verbosity: -1, emit: emit}
+
+	for _, fn := range opts {
+		fn(s)
+	}
+
+	return s
+}
+
+// Init receives the number of frames logr adds above the sink, to find the caller of a log.
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+	if s.next != nil {
+		s.next.Init(info)
+	}
+}
+
+// Enabled reports whether info logs of level are exported, or written by the next sink.
+func (s *Sink) Enabled(level int) bool {
+	return level <= s.verbosity || (s.next != nil && s.next.Enabled(level))
+}
+
+// Info exports an info log. logr only calls it for enabled levels.
+func (s *Sink) Info(level int, msg string, keysAndValues ...any) {
+	if s.next != nil && s.next.Enabled(level) {
+		s.next.Info(level, msg, keysAndValues...)
+	}
+	if level > s.verbosity {
+		return
+	}
+	severity := "INFO"
+	if level > 0 {
+		severity = "DEBUG"
+	}
+	s.export(severity, msg, nil, keysAndValues)
+}
+
+// Error exports an error log, and records err on the span of the context.
+func (s *Sink) Error(err error, msg string, keysAndValues ...any) {
+	if s.next != nil {
+		s.next.Error(err, msg, keysAndValues...)
+	}
+	s.export("ERROR", msg, err, keysAndValues)
+}
+
+// export exports a log with scout.RecordLog. The caller of the log is three frames up: export, the Info
+// or Error method of the sink and the frames added by logr.
+func (s *Sink) export(severity string, msg string, err error, keysAndValues []any) {
+	var attrs []attribute.KeyValue
+	if _, file, line, ok := runtime.Caller(s.callDepth + 2); ok {
+		attrs = append(attrs, semconv.CodeFilepathKey.String(file), semconv.CodeLineNumberKey.Int(line))
+	}
+	if s.name != "" {
+		attrs = append(attrs, attribute.String(LoggerNameKey, s.name))
+	}
+	ctx, kvs := keyValues(append(s.values[:len(s.values):len(s.values)], keysAndValues...))
+	attrs = append(attrs, kvs...)
+	if err != nil {
+		attrs = append(attrs, attribute.String("error", err.Error()))
+	}
+
+	s.emit(ctx, scout.LogRecord{
+		Time:       time.Now(),
+		Severity:   severity,
+		Message:    msg,
+		Attributes: attrs,
+		Error:      severity == "ERROR",
+		Mode:       s.mode,
+	}, err)
+}
+
+func emit(ctx context.Context, r scout.LogRecord, err error) {
+	if !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationKlog) {
+		return
+	}
+	if err != nil {
+		scout.RecordSpanError(trace.SpanFromContext(ctx), err)
+	}
+	scout.RecordLog(ctx, r)
+}
+
+// WithValues returns a sink adding the key/values to every log.
+func (s *Sink) WithValues(keysAndValues ...any) logr.LogSink {
+	c := *s
+	c.values = append(s.values[:len(s.values):len(s.values)], keysAndValues...)
+	if c.next != nil {
+		c.next = c.next.WithValues(keysAndValues...)
+	}
+	return &c
+}
+
+// WithName returns a sink whose logger name is suffixed with name.
+func (s *Sink) WithName(name string) logr.LogSink {
+	c := *s
+	if c.name == "" {
+		c.name = name
+	} else {
+		c.name = strings.Join([]string{c.name, name}, ".")
+	}
+	if c.next != nil {
+		c.next = c.next.WithName(name)
+	}
+	return &c
+}
+
+// WithCallDepth returns a sink finding the caller of a log depth more frames up, for helpers wrapping a
+// logger, as klog does.
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	c := *s
+	c.callDepth += depth
+	if next, ok := c.next.(logr.CallDepthLogSink); ok {
+		c.next = next.WithCallDepth(depth)
+	}
+	return &c
+}
+
+// keyValues returns the context among the values, and the other key/values as attributes.
+func keyValues(keysAndValues []any) (context.Context, []attribute.KeyValue) {
+	if len(keysAndValues)%2 != 0 {
+		keysAndValues = append(keysAndValues, "<no-value>")
+	}
+	ctx := context.TODO()
+	attrs := make([]attribute.KeyValue, 0, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		k, v := keysAndValues[i], keysAndValues[i+1]
+		if c, ok := v.(context.Context); ok {
+			ctx = c
+			continue
+		}
+		attrs = append(attrs, attribute.String(fmt.Sprint(k), fmt.Sprintf("%+v", v)))
+	}
+	return ctx, attrs
+}
@@ -0,0 +1,78 @@
+package klog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+type ctxKey struct{}
+
+type emitted struct {
+	ctx    context.Context
+	record scout.LogRecord
+	err    error
+}
+
+func TestSink(t *testing.T) {
+	var logs []emitted
+	sink := NewSink(WithVerbosity(1), WithLogMode(scout.LogModeBoth))
+	sink.emit = func(ctx context.Context, r scout.LogRecord, err error) {
+		logs = append(logs, emitted{ctx, r, err})
+	}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	logger := logr.New(sink).WithName("controller").WithName("pods").WithValues("namespace", "default")
+
+	logger.Info("reconciling", "ctx", ctx, "pod", "api")
+	logger.V(1).Info("cache hit")
+	logger.V(2).Info("dropped")
+	declined := errors.New("conflict")
+	logger.Error(declined, "reconcile failed", "dangling")
+
+	require.Len(t, logs, 3)
+	assert.Equal(t, ctx, logs[0].ctx)
+	assert.Equal(t, "INFO", logs[0].record.Severity)
+	assert.Equal(t, scout.LogModeBoth, logs[0].record.Mode)
+	assert.Equal(t, "DEBUG", logs[1].record.Severity)
+	assert.Equal(t, "ERROR", logs[2].record.Severity)
+	assert.True(t, logs[2].record.Error)
+	assert.Equal(t, declined, logs[2].err)
+
+	attrs := logs[0].record.Attributes
+	require.Len(t, attrs, 5)
+	assert.Equal(t, semconv.CodeFilepathKey, attrs[0].Key)
+	assert.Equal(t, "sink_test.go", filepath.Base(attrs[0].Value.AsString()))
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(LoggerNameKey, "controller.pods"),
+		attribute.String("namespace", "default"),
+		attribute.String("pod", "api"),
+	}, attrs[2:])
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(LoggerNameKey, "controller.pods"),
+		attribute.String("namespace", "default"),
+		attribute.String("dangling", "<no-value>"),
+		attribute.String("error", "conflict"),
+	}, logs[2].record.Attributes[2:])
+}
+
+func TestNextSink(t *testing.T) {
+	var buf bytes.Buffer
+	next := funcr.New(func(prefix, args string) { buf.WriteString(args + "\n") }, funcr.Options{Verbosity: 1})
+	sink := NewSink(WithSink(next.GetSink()))
+	sink.emit = func(context.Context, scout.LogRecord, error) { t.Fatalf("info log exported") }
+
+	logger := logr.New(sink).WithValues("pod", "api")
+	assert.True(t, logger.V(1).Enabled())
+	logger.V(1).Info("served")
+	assert.Equal(t, `"level"=1 "msg"="served" "pod"="api"`+"\n", buf.String())
+}
@@ -3,11 +3,12 @@ package log
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/scout-inc/scout-go"
 	"github.com/sirupsen/logrus"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -26,10 +27,54 @@ func WithLevels(levels ...logrus.Level) Option {
 	}
 }
 
+// WithTraceCoupling only exports logs written within a trace if the trace was sampled, or if an error
+// is logged in it. Logs of unsampled traces are buffered for the window, and exported with any error
+// logged in the trace during the window. Logs written outside of a trace are not affected.
+func WithTraceCoupling(window time.Duration) Option {
+	return func(h *Hook) {
+		h.coupling = newTraceCoupling(window)
+	}
+}
+
+// WithErrorStatusLevel sets the level at or above which logs are errors, setting the error status of
+// their span, flushing the logs coupled with their trace and bypassing sampling and rate limits.
+//
+// The default is logrus.ErrorLevel.
+func WithErrorStatusLevel(level logrus.Level) Option {
+	return func(h *Hook) {
+		h.errorStatusLevel = level
+		h.levelMapping = nil
+	}
+}
+
+// WithLevelMapping decides which logs are errors with isError rather than with an error status level,
+// for example to only treat panics and fatal logs as errors:
+//
+//	log.WithLevelMapping(func(level logrus.Level) bool { return level <= logrus.FatalLevel })
+func WithLevelMapping(isError func(level logrus.Level) bool) Option {
+	return func(h *Hook) {
+		h.levelMapping = isError
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(h *Hook) {
+		h.mode = mode
+	}
+}
+
 // Hook is a logrus hook that adds logs to the active span as events.
 type Hook struct {
 	levels           []logrus.Level
 	errorStatusLevel logrus.Level
+	levelMapping     func(level logrus.Level) bool
+	coupling         *traceCoupling
+	sampleRates      map[logrus.Level]float64
+	limiter          *rateLimiter
+	redactedFields   []string
+	redactedPatterns []*regexp.Regexp
+	mode             scout.LogMode
 }
 
 var _ logrus.Hook = (*Hook)(nil)
@@ -55,18 +100,40 @@ func NewHook(opts ...Option) *Hook {
 
 // Fire is a logrus hook that is fired on a new log entry.
 func (hook *Hook) Fire(entry *logrus.Entry) error {
+	// fast path: nothing will be exported, so avoid building the log entirely
+	if !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationLogrus) || !hook.sampled(entry.Level) {
+		return nil
+	}
+
 	ctx := entry.Context
 	if ctx == nil {
 		ctx = context.TODO()
 	}
 
-	span, _ := scout.StartTraceWithTimestamp(ctx, "scout.go.log", entry.Time, []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)})
-	defer scout.EndTrace(span)
-
-	attrs := []attribute.KeyValue{
-		LogSeverityKey.String(levelString(entry.Level)),
-		LogMessageKey.String(entry.Message),
+	if hook.coupling != nil {
+		if psc := trace.SpanContextFromContext(ctx); psc.IsValid() {
+			for _, r := range hook.coupling.add(psc, hook.newRecord(ctx, entry), hook.isError(entry.Level)) {
+				hook.export(r, true)
+			}
+			return nil
+		}
 	}
+
+	hook.export(hook.newRecord(ctx, entry), false)
+	return nil
+}
+
+// record holds what is exported of a log entry, as logrus reuses entries once the hooks have fired.
+type record struct {
+	ctx     context.Context
+	time    time.Time
+	level   logrus.Level
+	message string
+	attrs   []attribute.KeyValue
+}
+
+func (hook *Hook) newRecord(ctx context.Context, entry *logrus.Entry) record {
+	attrs := make([]attribute.KeyValue, 0, 3+len(entry.Data))
 	if entry.Caller != nil {
 		if entry.Caller.Function != "" {
 			attrs = append(attrs, semconv.CodeFunctionKey.String(entry.Caller.Function))
@@ -78,16 +145,38 @@ func (hook *Hook) Fire(entry *logrus.Entry) error {
 	}
 
 	for k, v := range entry.Data {
+		if hook.isRedactedField(k) {
+			attrs = append(attrs, attribute.String(k, scout.RedactedValue))
+			continue
+		}
 		attrs = append(attrs, attribute.String(k, fmt.Sprintf("%+v", v)))
 	}
+	if entry.Level <= logrus.FatalLevel {
+		attrs = append(attrs, scout.GoroutineDumpAttributes()...)
+	}
+	return record{ctx: ctx, time: entry.Time, level: entry.Level, message: entry.Message, attrs: attrs}
+}
 
-	span.AddEvent(scout.LogEvent, trace.WithAttributes(attrs...))
+// export exports the record with scout.RecordLog. Force sampled records are exported regardless
+// of the sampling rates.
+func (hook *Hook) export(r record, forceSample bool) {
+	scout.RecordLog(r.ctx, scout.LogRecord{
+		Time:        r.time,
+		Severity:    levelString(r.level),
+		Message:     r.message,
+		Attributes:  r.attrs,
+		Error:       hook.isError(r.level),
+		ForceSample: forceSample,
+		Mode:        hook.mode,
+	})
+}
 
-	if entry.Level <= hook.errorStatusLevel {
-		span.SetStatus(codes.Error, entry.Message)
+// isError reports whether logs of level are errors.
+func (hook *Hook) isError(level logrus.Level) bool {
+	if hook.levelMapping != nil {
+		return hook.levelMapping(level)
 	}
-
-	return nil
+	return level <= hook.errorStatusLevel
 }
 
 // Levels returns logrus levels on which this hook is fired.
@@ -0,0 +1,18 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorStatusLevel(t *testing.T) {
+	hook := NewHook(WithErrorStatusLevel(logrus.WarnLevel))
+	assert.True(t, hook.isError(logrus.WarnLevel))
+	assert.False(t, hook.isError(logrus.InfoLevel))
+
+	hook = NewHook(WithLevelMapping(func(level logrus.Level) bool { return level <= logrus.FatalLevel }))
+	assert.False(t, hook.isError(logrus.ErrorLevel))
+	assert.True(t, hook.isError(logrus.PanicLevel))
+}
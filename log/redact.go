@@ -0,0 +1,40 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WithRedactedFields redacts the values of the entry fields with the given names before they are exported,
+// so personal data and credentials logged as fields do not reach Scout. Names are matched case-insensitively.
+func WithRedactedFields(names ...string) Option {
+	return func(h *Hook) {
+		for _, name := range names {
+			h.redactedFields = append(h.redactedFields, strings.ToLower(name))
+		}
+	}
+}
+
+// WithRedactedFieldPattern redacts the values of the entry fields whose names match pattern, such as
+// regexp.MustCompile(`(?i)secret|token`).
+func WithRedactedFieldPattern(pattern *regexp.Regexp) Option {
+	return func(h *Hook) {
+		h.redactedPatterns = append(h.redactedPatterns, pattern)
+	}
+}
+
+// isRedactedField reports whether the value of the field named name is redacted.
+func (hook *Hook) isRedactedField(name string) bool {
+	lower := strings.ToLower(name)
+	for _, redacted := range hook.redactedFields {
+		if lower == redacted {
+			return true
+		}
+	}
+	for _, pattern := range hook.redactedPatterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
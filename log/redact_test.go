@@ -0,0 +1,32 @@
+package log
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRedactedFields(t *testing.T) {
+	hook := NewHook(WithRedactedFields("Password"), WithRedactedFieldPattern(regexp.MustCompile(`(?i)token`)))
+	entry := logrus.WithFields(logrus.Fields{
+		"password":      "hunter2",
+		"refresh_Token": "abc",
+		"user":          "ada",
+	})
+	r := hook.newRecord(context.Background(), entry)
+
+	attrs := map[attribute.Key]string{}
+	for _, kv := range r.attrs {
+		attrs[kv.Key] = kv.Value.AsString()
+	}
+	assert.Equal(t, map[attribute.Key]string{
+		"password":      scout.RedactedValue,
+		"refresh_Token": scout.RedactedValue,
+		"user":          "ada",
+	}, attrs)
+}
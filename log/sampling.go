@@ -0,0 +1,63 @@
+package log
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithSampleRate exports only a fraction, rate, of the logs of level, for noisy debug and info logs.
+// Error logs are always exported, see WithErrorStatusLevel.
+func WithSampleRate(level logrus.Level, rate float64) Option {
+	return func(h *Hook) {
+		if h.sampleRates == nil {
+			h.sampleRates = map[logrus.Level]float64{}
+		}
+		h.sampleRates[level] = rate
+	}
+}
+
+// WithRateLimit exports at most perSecond logs per second, dropping the others. Error logs are always
+// exported, and are not counted against the limit.
+func WithRateLimit(perSecond int) Option {
+	return func(h *Hook) {
+		h.limiter = &rateLimiter{limit: perSecond, now: time.Now}
+	}
+}
+
+// sampled reports whether a log of level is exported, according to the sample rates and the rate limit.
+func (hook *Hook) sampled(level logrus.Level) bool {
+	if hook.isError(level) {
+		return true
+	}
+	if rate, ok := hook.sampleRates[level]; ok && rand.Float64() >= rate {
+		return false
+	}
+	return hook.limiter == nil || hook.limiter.allow()
+}
+
+// rateLimiter allows up to limit events per second.
+type rateLimiter struct {
+	limit int
+	now   func() time.Time
+
+	mu     sync.Mutex
+	window time.Time
+	count  int
+}
+
+func (l *rateLimiter) allow() bool {
+	now := l.now().Truncate(time.Second)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !now.Equal(l.window) {
+		l.window, l.count = now, 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
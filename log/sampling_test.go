@@ -0,0 +1,32 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleRate(t *testing.T) {
+	hook := NewHook(WithSampleRate(logrus.DebugLevel, 0), WithSampleRate(logrus.InfoLevel, 1), WithSampleRate(logrus.ErrorLevel, 0))
+	assert.False(t, hook.sampled(logrus.DebugLevel))
+	assert.True(t, hook.sampled(logrus.InfoLevel))
+	assert.True(t, hook.sampled(logrus.WarnLevel))
+	// errors are always exported
+	assert.True(t, hook.sampled(logrus.ErrorLevel))
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hook := NewHook(WithRateLimit(2))
+	hook.limiter.now = func() time.Time { return now }
+
+	assert.True(t, hook.sampled(logrus.InfoLevel))
+	assert.True(t, hook.sampled(logrus.InfoLevel))
+	assert.False(t, hook.sampled(logrus.InfoLevel))
+	assert.True(t, hook.sampled(logrus.ErrorLevel))
+
+	now = now.Add(time.Second)
+	assert.True(t, hook.sampled(logrus.InfoLevel))
+}
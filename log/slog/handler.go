@@ -0,0 +1,196 @@
+// Package slog provides a log/slog handler that ships records to Scout, for applications logging with
+// the standard library rather than logrus.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Option applies a configuration to the given handler.
+type Option func(h *Handler)
+
+// WithLevel sets the minimum level of the records exported to Scout.
+//
+// The default is slog.LevelWarn.
+func WithLevel(level slog.Leveler) Option {
+	return func(h *Handler) {
+		h.level = level
+	}
+}
+
+// WithHandler also passes the records to next, such as a slog.TextHandler, so they are still written
+// out locally.
+func WithHandler(next slog.Handler) Option {
+	return func(h *Handler) {
+		h.next = next
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(h *Handler) {
+		h.mode = mode
+	}
+}
+
+// Handler is a slog handler that exports records to Scout.
+type Handler struct {
+	level            slog.Leveler
+	errorStatusLevel slog.Level
+	mode             scout.LogMode
+	next             slog.Handler
+
+	// attrs are the attributes added with WithAttrs, and prefix the groups opened with WithGroup,
+	// as a dotted prefix of the keys of the attributes added after them.
+	attrs  []attribute.KeyValue
+	prefix string
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a slog handler, for example:
+//
+//	slog.SetDefault(slog.New(scoutslog.NewHandler(
+//		scoutslog.WithHandler(slog.NewTextHandler(os.Stderr, nil)),
+//	)))
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{
+		level:            slog.LevelWarn,
+		errorStatusLevel: slog.LevelError,
+	}
+
+	for _, fn := range opts {
+		fn(h)
+	}
+
+	return h
+}
+
+// Enabled reports whether records of level are exported, or handled by the handler set with WithHandler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() || h.next != nil && h.next.Enabled(ctx, level)
+}
+
+// Handle exports the record to Scout if its level is enabled.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		err = h.next.Handle(ctx, r)
+	}
+
+	if r.Level < h.level.Level() || !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationSlog) {
+		return err
+	}
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+	h.export(ctx, r)
+	return err
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	c := *h
+	c.attrs = make([]attribute.KeyValue, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(c.attrs, h.attrs)
+	for _, a := range attrs {
+		c.attrs = appendAttr(c.attrs, h.prefix, a)
+	}
+	if h.next != nil {
+		c.next = h.next.WithAttrs(attrs)
+	}
+	return &c
+}
+
+// WithGroup returns a handler prefixing the keys of the attributes added after it with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.prefix = h.prefix + name + "."
+	if h.next != nil {
+		c.next = h.next.WithGroup(name)
+	}
+	return &c
+}
+
+// attributes returns the attributes exported for the fields and the source of r.
+func (h *Handler) attributes(r slog.Record) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3+len(h.attrs)+r.NumAttrs())
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		if frame.Function != "" {
+			attrs = append(attrs, semconv.CodeFunctionKey.String(frame.Function))
+		}
+		if frame.File != "" {
+			attrs = append(attrs, semconv.CodeFilepathKey.String(frame.File))
+			attrs = append(attrs, semconv.CodeLineNumberKey.Int(frame.Line))
+		}
+	}
+
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, h.prefix, a)
+		return true
+	})
+	return attrs
+}
+
+// export exports the record with scout.RecordLog.
+func (h *Handler) export(ctx context.Context, r slog.Record) {
+	scout.RecordLog(ctx, scout.LogRecord{
+		Time:       r.Time,
+		Severity:   levelString(r.Level),
+		Message:    r.Message,
+		Attributes: h.attributes(r),
+		Error:      r.Level >= h.errorStatusLevel,
+		Mode:       h.mode,
+	})
+}
+
+// appendAttr appends a as a string attribute, flattening groups into dotted keys.
+func appendAttr(attrs []attribute.KeyValue, prefix string, a slog.Attr) []attribute.KeyValue {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendAttr(attrs, prefix, ga)
+		}
+		return attrs
+	case slog.KindAny:
+		return append(attrs, attribute.String(prefix+a.Key, fmt.Sprintf("%+v", a.Value.Any())))
+	default:
+		return append(attrs, attribute.String(prefix+a.Key, a.Value.String()))
+	}
+}
+
+// levelString returns the severity of level as the logrus hook reports it, rounding custom levels down
+// to the closest standard level.
+func levelString(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARN"
+	case level >= slog.LevelInfo:
+		return "INFO"
+	}
+	return "DEBUG"
+}
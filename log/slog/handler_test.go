@@ -0,0 +1,111 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestAttributes(t *testing.T) {
+	h := NewHandler().
+		WithAttrs([]slog.Attr{slog.String("service", "api")}).
+		WithGroup("req").
+		WithAttrs([]slog.Attr{slog.Int("id", 7)}).(*Handler)
+
+	r := slog.NewRecord(time.Now(), slog.LevelWarn+1, "slow request", 0)
+	r.AddAttrs(
+		slog.Duration("took", time.Second),
+		slog.Any("err", errors.New("timeout")),
+		slog.Group("user", slog.String("name", "ada")),
+		slog.Group("empty"),
+	)
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("service", "api"),
+		attribute.String("req.id", "7"),
+		attribute.String("req.took", "1s"),
+		attribute.String("req.err", "timeout"),
+		attribute.String("req.user.name", "ada"),
+	}, h.attributes(r))
+}
+
+func TestAttributesSource(t *testing.T) {
+	var attrs []attribute.KeyValue
+	logger := slog.New(handlerFunc(func(r slog.Record) {
+		attrs = NewHandler().attributes(r)
+	}))
+	logger.Error("failed")
+
+	keys := make([]attribute.Key, 0, len(attrs))
+	for _, a := range attrs {
+		keys = append(keys, a.Key)
+	}
+	assert.Equal(t, []attribute.Key{semconv.CodeFunctionKey, semconv.CodeFilepathKey, semconv.CodeLineNumberKey}, keys)
+	assert.Equal(t, "github.com/scout-inc/scout-go/log/slog.TestAttributesSource", attrs[0].Value.AsString())
+}
+
+func TestEnabled(t *testing.T) {
+	ctx := context.Background()
+	h := NewHandler()
+	assert.False(t, h.Enabled(ctx, slog.LevelInfo))
+	assert.True(t, h.Enabled(ctx, slog.LevelWarn))
+
+	h = NewHandler(WithLevel(slog.LevelDebug))
+	assert.True(t, h.Enabled(ctx, slog.LevelDebug))
+
+	h = NewHandler(WithHandler(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	assert.True(t, h.Enabled(ctx, slog.LevelInfo))
+	assert.False(t, h.Enabled(ctx, slog.LevelDebug))
+}
+
+func TestNextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(WithHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))))
+	logger.WithGroup("req").Info("served", "status", 200)
+
+	assert.False(t, scout.IsRunning())
+	assert.Equal(t, "level=INFO msg=served req.status=200\n", buf.String())
+}
+
+func TestLevelString(t *testing.T) {
+	for level, want := range map[slog.Level]string{
+		slog.LevelDebug - 4: "DEBUG",
+		slog.LevelDebug:     "DEBUG",
+		slog.LevelInfo:      "INFO",
+		slog.LevelInfo + 2:  "INFO",
+		slog.LevelWarn:      "WARN",
+		slog.LevelError:     "ERROR",
+		slog.LevelError + 4: "ERROR",
+	} {
+		assert.Equal(t, want, levelString(level), level.String())
+	}
+}
+
+// handlerFunc is a slog handler calling itself on every record.
+type handlerFunc func(r slog.Record)
+
+func (f handlerFunc) Enabled(context.Context, slog.Level) bool { return true }
+
+func (f handlerFunc) Handle(_ context.Context, r slog.Record) error {
+	f(r)
+	return nil
+}
+
+func (f handlerFunc) WithAttrs([]slog.Attr) slog.Handler { return f }
+
+func (f handlerFunc) WithGroup(string) slog.Handler { return f }
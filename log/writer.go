@@ -0,0 +1,74 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/sirupsen/logrus"
+)
+
+// maxLineLength is the longest line a writer buffers, longer lines are exported in parts.
+const maxLineLength = 64 << 10
+
+// writer exports each line written to it as a log.
+type writer struct {
+	mu   sync.Mutex
+	buf  []byte
+	emit func(line string)
+}
+
+// NewWriter returns a writer exporting each line written to it as a log of level, within the trace and
+// the Scout request of ctx, for the standard library logger or libraries writing plain lines:
+//
+//	stdlog.SetOutput(io.MultiWriter(os.Stderr, log.NewWriter(context.Background(), logrus.InfoLevel)))
+func NewWriter(ctx context.Context, level logrus.Level) io.Writer {
+	severity := levelString(level)
+	return &writer{emit: func(line string) {
+		if !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationLogWriter) {
+			return
+		}
+		scout.RecordLog(ctx, scout.LogRecord{
+			Time:     time.Now(),
+			Severity: severity,
+			Message:  line,
+			Error:    level <= logrus.ErrorLevel,
+		})
+	}}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	for len(w.buf) >= maxLineLength {
+		w.line(w.buf[:maxLineLength])
+		w.buf = w.buf[maxLineLength:]
+	}
+	// keep the partial line without holding on to the lines already exported
+	if len(w.buf) == 0 {
+		w.buf = nil
+	} else {
+		w.buf = append([]byte(nil), w.buf...)
+	}
+	return len(p), nil
+}
+
+func (w *writer) line(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	w.emit(string(line))
+}
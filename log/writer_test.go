@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	var lines []string
+	w := &writer{emit: func(line string) { lines = append(lines, line) }}
+
+	_, _ = w.Write([]byte("first\nsec"))
+	_, _ = w.Write([]byte("ond\r\n\n  \nthi"))
+	assert.Equal(t, []string{"first", "second"}, lines)
+	_, _ = w.Write([]byte("rd\n"))
+	assert.Equal(t, []string{"first", "second", "third"}, lines)
+
+	lines = nil
+	stdlog.New(w, "app: ", 0).Printf("connected to %s", "db")
+	assert.Equal(t, []string{"app: connected to db"}, lines)
+
+	lines = nil
+	_, _ = w.Write(bytes.Repeat([]byte("a"), maxLineLength+1))
+	assert.Equal(t, []string{strings.Repeat("a", maxLineLength)}, lines)
+	assert.Len(t, w.buf, 1)
+}
@@ -0,0 +1,189 @@
+// Package zap provides a zapcore.Core that ships logs to Scout, for applications logging with
+// uber-go/zap rather than logrus.
+package zap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// contextKey is the key of the field set by Context.
+const contextKey = "scout.context"
+
+// Option applies a configuration to the given core.
+type Option func(c *Core)
+
+// WithLevel sets the levels of the logs exported to Scout.
+//
+// The default is zapcore.WarnLevel and above.
+func WithLevel(level zapcore.LevelEnabler) Option {
+	return func(c *Core) {
+		c.LevelEnabler = level
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(c *Core) {
+		c.mode = mode
+	}
+}
+
+// Core is a zapcore.Core that exports logs to Scout.
+type Core struct {
+	zapcore.LevelEnabler
+	errorStatusLevel zapcore.Level
+	mode             scout.LogMode
+
+	// ctx is the context set with a Context field, and fields the fields added with With.
+	ctx    context.Context
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// NewCore returns a zap core, to be combined with the core writing the logs out, for example:
+//
+//	logger := zap.New(zapcore.NewTee(core, scoutzap.NewCore()))
+func NewCore(opts ...Option) *Core {
+	c := &Core{
+		LevelEnabler:     zapcore.WarnLevel,
+		errorStatusLevel: zapcore.ErrorLevel,
+	}
+
+	for _, fn := range opts {
+		fn(c)
+	}
+
+	return c
+}
+
+// WrapCore returns a zap option adding a Scout core to a logger, for example:
+//
+//	logger, err := zap.NewProduction(scoutzap.WrapCore())
+func WrapCore(opts ...Option) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, NewCore(opts...))
+	})
+}
+
+// Context returns a field exporting the logs written with it within the trace active in ctx. The field
+// is skipped by the other cores:
+//
+//	logger.Error("charge failed", scoutzap.Context(ctx), zap.Error(err))
+func Context(ctx context.Context) zapcore.Field {
+	return zapcore.Field{Key: contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// With returns a core adding fields to every log.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...)
+	for _, f := range fields {
+		if ctx, ok := fieldContext(f); ok {
+			clone.ctx = ctx
+			continue
+		}
+		clone.fields = append(clone.fields, f)
+	}
+	return &clone
+}
+
+// Check adds the core to ce if the level of ent is enabled.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write exports the log.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationZap) {
+		return nil
+	}
+
+	ctx := c.ctx
+	for _, f := range fields {
+		if fctx, ok := fieldContext(f); ok {
+			ctx = fctx
+		}
+	}
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+
+	scout.RecordLog(ctx, scout.LogRecord{
+		Time:       ent.Time,
+		Severity:   levelString(ent.Level),
+		Message:    ent.Message,
+		Attributes: c.attributes(ent, fields),
+		Error:      ent.Level >= c.errorStatusLevel,
+		Mode:       c.mode,
+	})
+	return nil
+}
+
+// Sync does nothing, as logs are flushed with the rest of the telemetry.
+func (c *Core) Sync() error {
+	return nil
+}
+
+// attributes returns the attributes exported for the fields and the caller of ent.
+func (c *Core) attributes(ent zapcore.Entry, fields []zapcore.Field) []attribute.KeyValue {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 3+len(enc.Fields))
+	if ent.Caller.Defined {
+		if ent.Caller.Function != "" {
+			attrs = append(attrs, semconv.CodeFunctionKey.String(ent.Caller.Function))
+		}
+		if ent.Caller.File != "" {
+			attrs = append(attrs, semconv.CodeFilepathKey.String(ent.Caller.File))
+			attrs = append(attrs, semconv.CodeLineNumberKey.Int(ent.Caller.Line))
+		}
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, fmt.Sprintf("%+v", enc.Fields[k])))
+	}
+	if ent.Level >= zapcore.PanicLevel {
+		attrs = append(attrs, scout.GoroutineDumpAttributes()...)
+	}
+	return attrs
+}
+
+// fieldContext returns the context of a field returned by Context.
+func fieldContext(f zapcore.Field) (context.Context, bool) {
+	if f.Type != zapcore.SkipType || f.Key != contextKey {
+		return nil, false
+	}
+	ctx, ok := f.Interface.(context.Context)
+	return ctx, ok
+}
+
+// levelString returns the severity of level as the logrus hook reports it.
+func levelString(level zapcore.Level) string {
+	if level == zapcore.DPanicLevel {
+		return "PANIC"
+	}
+	return level.CapitalString()
+}
@@ -0,0 +1,70 @@
+package zap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type ctxKey struct{}
+
+func TestAttributes(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	c := NewCore().With([]zapcore.Field{zap.String("service", "api"), Context(ctx)}).(*Core)
+	assert.Equal(t, ctx, c.ctx)
+
+	ent := zapcore.Entry{
+		Level:   zapcore.ErrorLevel,
+		Time:    time.Now(),
+		Message: "charge failed",
+		Caller:  zapcore.NewEntryCaller(0, "/app/charge.go", 42, true),
+	}
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("code.filepath", "/app/charge.go"),
+		attribute.Int("code.lineno", 42),
+		attribute.String("amount", "12"),
+		attribute.String("error", "declined"),
+		attribute.String("service", "api"),
+		attribute.String("took", "1s"),
+	}, c.attributes(ent, []zapcore.Field{
+		zap.Int("amount", 12),
+		zap.Duration("took", time.Second),
+		zap.Error(errors.New("declined")),
+	}))
+}
+
+func TestCheck(t *testing.T) {
+	c := NewCore()
+	assert.Nil(t, c.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil))
+	assert.NotNil(t, c.Check(zapcore.Entry{Level: zapcore.WarnLevel}, nil))
+
+	c = NewCore(WithLevel(zapcore.DebugLevel))
+	assert.NotNil(t, c.Check(zapcore.Entry{Level: zapcore.DebugLevel}, nil))
+}
+
+func TestContextField(t *testing.T) {
+	ctx := context.Background()
+	got, ok := fieldContext(Context(ctx))
+	assert.True(t, ok)
+	assert.Equal(t, ctx, got)
+
+	_, ok = fieldContext(zap.Skip())
+	assert.False(t, ok)
+
+	// the other cores skip the field
+	enc := zapcore.NewMapObjectEncoder()
+	Context(ctx).AddTo(enc)
+	assert.Empty(t, enc.Fields)
+}
+
+func TestLevelString(t *testing.T) {
+	assert.Equal(t, "WARN", levelString(zapcore.WarnLevel))
+	assert.Equal(t, "PANIC", levelString(zapcore.DPanicLevel))
+	assert.Equal(t, "FATAL", levelString(zapcore.FatalLevel))
+}
@@ -0,0 +1,219 @@
+// Package zerolog provides a zerolog writer that ships logs to Scout, for applications logging with
+// rs/zerolog rather than logrus.
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fields added by Hook to correlate the logs with the trace and the Scout request they were written in.
+const (
+	TraceParentFieldName  = "traceparent"
+	ScoutRequestFieldName = "scout_request"
+)
+
+// Option applies a configuration to the given writer.
+type Option func(w *Writer)
+
+// WithLevel sets the minimum level of the logs exported to Scout.
+//
+// The default is zerolog.WarnLevel.
+func WithLevel(level zerolog.Level) Option {
+	return func(w *Writer) {
+		w.level = level
+	}
+}
+
+// WithLogMode sets how logs are exported, see scout.LogMode.
+func WithLogMode(mode scout.LogMode) Option {
+	return func(w *Writer) {
+		w.mode = mode
+	}
+}
+
+// Writer is a zerolog writer that parses the JSON logs written to it and exports them to Scout. Add Hook
+// to the logger for the logs written with a context to be correlated with its trace:
+//
+//	logger := zerolog.New(zerolog.MultiLevelWriter(os.Stderr, scoutzerolog.NewWriter())).Hook(scoutzerolog.Hook{})
+//	logger.Error().Ctx(ctx).Err(err).Msg("charge failed")
+type Writer struct {
+	level            zerolog.Level
+	errorStatusLevel zerolog.Level
+	mode             scout.LogMode
+}
+
+var _ zerolog.LevelWriter = (*Writer)(nil)
+
+// NewWriter returns a zerolog writer.
+func NewWriter(opts ...Option) *Writer {
+	w := &Writer{
+		level:            zerolog.WarnLevel,
+		errorStatusLevel: zerolog.ErrorLevel,
+	}
+
+	for _, fn := range opts {
+		fn(w)
+	}
+
+	return w
+}
+
+// Write exports a log, reading its level from the level field.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel exports a log of the given level. Writing always succeeds, so a log that cannot be
+// parsed does not fail the other writers of the logger.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !scout.IsRunning() || !scout.IntegrationEnabled(scout.IntegrationZerolog) {
+		return len(p), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return len(p), nil
+	}
+	if level == zerolog.NoLevel {
+		if s, ok := fields[zerolog.LevelFieldName].(string); ok {
+			level, _ = zerolog.ParseLevel(s)
+		}
+	}
+	// logs written without a level, with Log, are exported as information
+	if level == zerolog.NoLevel {
+		level = zerolog.InfoLevel
+	}
+	if level < w.level {
+		return len(p), nil
+	}
+
+	w.export(level, fields)
+	return len(p), nil
+}
+
+// export exports the log with scout.RecordLog, within the trace and the Scout request of its
+// correlation fields.
+func (w *Writer) export(level zerolog.Level, fields map[string]interface{}) {
+	ctx := context.TODO()
+	if tp, ok := fields[TraceParentFieldName].(string); ok {
+		ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{TraceParentFieldName: tp})
+	}
+	if r, ok := fields[ScoutRequestFieldName].(string); ok {
+		ctx = scout.InterceptRequestHeader(ctx, r)
+	}
+
+	message, _ := fields[zerolog.MessageFieldName].(string)
+	scout.RecordLog(ctx, scout.LogRecord{
+		Time:       logTime(fields[zerolog.TimestampFieldName]),
+		Severity:   strings.ToUpper(level.String()),
+		Message:    message,
+		Attributes: attributes(level, fields),
+		Error:      level >= w.errorStatusLevel,
+		Mode:       w.mode,
+	})
+}
+
+// attributes returns the attributes exported for the fields and the caller of a log.
+func attributes(level zerolog.Level, fields map[string]interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2+len(fields))
+	if caller, ok := fields[zerolog.CallerFieldName].(string); ok {
+		file, line := caller, ""
+		if i := strings.LastIndexByte(caller, ':'); i > 0 {
+			file, line = caller[:i], caller[i+1:]
+		}
+		attrs = append(attrs, semconv.CodeFilepathKey.String(file))
+		if n, err := strconv.Atoi(line); err == nil {
+			attrs = append(attrs, semconv.CodeLineNumberKey.Int(n))
+		}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		switch k {
+		case zerolog.LevelFieldName, zerolog.MessageFieldName, zerolog.TimestampFieldName, zerolog.CallerFieldName,
+			TraceParentFieldName, ScoutRequestFieldName:
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := fields[k]
+		if _, ok := v.(string); !ok {
+			// objects and arrays are kept as JSON
+			if b, err := json.Marshal(v); err == nil {
+				v = string(b)
+			}
+		}
+		attrs = append(attrs, attribute.String(k, fmt.Sprintf("%+v", v)))
+	}
+	if level >= zerolog.FatalLevel {
+		attrs = append(attrs, scout.GoroutineDumpAttributes()...)
+	}
+	return attrs
+}
+
+// logTime returns the time of a log from its timestamp field, formatted as zerolog.TimeFieldFormat, or
+// the current time if it has none.
+func logTime(v interface{}) time.Time {
+	switch v := v.(type) {
+	case string:
+		if t, err := time.Parse(zerolog.TimeFieldFormat, v); err == nil {
+			return t
+		}
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			break
+		}
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnix:
+			return time.Unix(n, 0)
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(n)
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(n)
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, n)
+		}
+	}
+	return time.Now()
+}
+
+// Hook adds the traceparent and scout_request fields to the logs written with a context, with Ctx, so
+// Writer exports them within the trace and the Scout request of the context.
+type Hook struct{}
+
+var _ zerolog.Hook = Hook{}
+
+// Run adds the correlation fields of the context of e, if any.
+func (Hook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	if ctx == nil {
+		return
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		carrier := propagation.MapCarrier{}
+		propagation.TraceContext{}.Inject(ctx, carrier)
+		e.Str(TraceParentFieldName, carrier.Get(TraceParentFieldName))
+	}
+	if r := scout.HeaderValue(ctx); r != "" {
+		e.Str(ScoutRequestFieldName, r)
+	}
+}
@@ -0,0 +1,57 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAttributes(t *testing.T) {
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader([]byte(`{"level":"error","time":"2024-01-02T03:04:05Z","caller":"/app/charge.go:42","message":"charge failed","error":"declined","amount":12,"user":{"id":7},"traceparent":"00-01000000000000000000000000000000-0100000000000000-01"}`)))
+	dec.UseNumber()
+	assert.NoError(t, dec.Decode(&fields))
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("code.filepath", "/app/charge.go"),
+		attribute.Int("code.lineno", 42),
+		attribute.String("amount", "12"),
+		attribute.String("error", "declined"),
+		attribute.String("user", `{"id":7}`),
+	}, attributes(zerolog.ErrorLevel, fields))
+}
+
+func TestLogTime(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), logTime("2024-01-02T03:04:05Z").UTC())
+
+	prev := zerolog.TimeFieldFormat
+	defer func() { zerolog.TimeFieldFormat = prev }()
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnixMs
+	assert.Equal(t, time.UnixMilli(1704164645000), logTime(json.Number("1704164645000")))
+
+	assert.WithinDuration(t, time.Now(), logTime(nil), time.Second)
+}
+
+func TestHook(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = scout.ContextWithSessionID(ctx, "session")
+	ctx = scout.ContextWithRequestID(ctx, "request")
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(Hook{})
+	logger.Error().Ctx(ctx).Msg("with context")
+	logger.Error().Msg("without context")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.JSONEq(t, `{"level":"error","traceparent":"00-01000000000000000000000000000000-0100000000000000-01","scout_request":"session/request","message":"with context"}`, string(lines[0]))
+	assert.JSONEq(t, `{"level":"error","message":"without context"}`, string(lines[1]))
+}
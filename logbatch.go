@@ -0,0 +1,121 @@
+package scout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithLogBatching buffers the logs exported as span events, see WithLogSpanEvents, and exports them
+// every interval on one carrier span per session, request and parent span, with an event per log, rather
+// than a span per log. A batch is exported early once it holds size logs, or as many events as a span
+// can hold if size is not positive.
+func WithLogBatching(interval time.Duration, size int) Option {
+	return option(func(conf *config) {
+		conf.logBatchInterval = interval
+		conf.logBatchSize = size
+	})
+}
+
+// maxBatchedLogs bounds the logs buffered per carrier span.
+func maxBatchedLogs() int {
+	conf := loadConfig()
+	if conf.logBatchSize > 0 {
+		return min(conf.logBatchSize, maxSpanEvents())
+	}
+	return maxSpanEvents()
+}
+
+type logBatch struct {
+	// ctx carries the identifiers of the batch, detached from the context the logs were recorded in
+	ctx     context.Context
+	records []LogRecord
+}
+
+var logBatches struct {
+	mu      sync.Mutex
+	batches map[batchKey]*logBatch
+}
+
+func startLogBatching() {
+	conf := loadConfig()
+	if conf.logBatchInterval <= 0 {
+		return
+	}
+	startPeriodic(conf.logBatchInterval, func(context.Context) {
+		flushLogs()
+	})
+	// flush the remaining logs before the exporters shut down
+	onStopWorkers(flushLogs)
+}
+
+func addLog(ctx context.Context, r LogRecord) {
+	key := newBatchKey(ctx)
+
+	logBatches.mu.Lock()
+	if logBatches.batches == nil {
+		logBatches.batches = map[batchKey]*logBatch{}
+	}
+	batch, ok := logBatches.batches[key]
+	if !ok {
+		batch = &logBatch{ctx: batchContext(ctx)}
+		logBatches.batches[key] = batch
+	}
+	batch.records = append(batch.records, r)
+	full := len(batch.records) >= maxBatchedLogs()
+	if full {
+		delete(logBatches.batches, key)
+	}
+	logBatches.mu.Unlock()
+
+	if full {
+		batch.export()
+	}
+}
+
+// flushLogs exports the buffered logs.
+func flushLogs() {
+	logBatches.mu.Lock()
+	batches := logBatches.batches
+	logBatches.batches = nil
+	logBatches.mu.Unlock()
+	for _, batch := range batches {
+		batch.export()
+	}
+}
+
+func (b *logBatch) export() {
+	start, end := b.records[0].Time, b.records[0].Time
+	forceSample := false
+	for _, r := range b.records {
+		if r.Time.Before(start) {
+			start = r.Time
+		}
+		if r.Time.After(end) {
+			end = r.Time
+		}
+		forceSample = forceSample || r.ForceSample
+	}
+	opts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}
+	if forceSample {
+		opts = append(opts, trace.WithAttributes(attribute.Bool(ForceSampleAttribute, true)))
+	}
+	span, _ := StartTraceWithTimestamp(b.ctx, "scout.go.log", start, opts)
+	defer span.End(trace.WithTimestamp(end))
+	if !span.IsRecording() {
+		return
+	}
+	errored := false
+	for _, r := range b.records {
+		span.AddEvent(LogEvent, trace.WithAttributes(logEventAttributes(r)...), trace.WithTimestamp(r.Time))
+		// the status describes the first error of the batch
+		if r.Error && !errored {
+			span.SetStatus(codes.Error, r.Message)
+			errored = true
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package scout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestLogBatching(t *testing.T) {
+	recorder := recordSpans(t)
+
+	request := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "request")
+	now := time.Now()
+	addLog(request, LogRecord{Time: now, Severity: "INFO", Message: "charging"})
+	addLog(request, LogRecord{Time: now.Add(time.Second), Severity: "ERROR", Message: "charge failed", Error: true})
+	addLog(context.Background(), LogRecord{Time: now, Severity: "WARN", Message: "queue full"})
+	flushLogs()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		if len(span.Events()) == 1 {
+			assert.Equal(t, codes.Unset, span.Status().Code)
+			continue
+		}
+		require.Len(t, span.Events(), 2)
+		assert.Contains(t, span.Events()[1].Attributes, attribute.String(LogMessageAttribute, "charge failed"))
+		assert.Equal(t, now.Add(time.Second), span.Events()[1].Time)
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, now, span.StartTime())
+		assert.Equal(t, now.Add(time.Second), span.EndTime())
+	}
+
+	flushLogs()
+	assert.Len(t, recorder.Ended(), 2)
+}
+
+func TestLogBatchingExportsFullBatches(t *testing.T) {
+	recorder := recordSpans(t)
+	useConfig(t, &config{})
+	updateConfig(WithLogBatching(time.Second, 3).apply)
+
+	for i := 0; i < 4; i++ {
+		addLog(context.Background(), LogRecord{Time: time.Now(), Severity: "INFO", Message: "job done"})
+	}
+	require.Len(t, recorder.Ended(), 1)
+	assert.Len(t, recorder.Ended()[0].Events(), 3)
+	flushLogs()
+	assert.Len(t, recorder.Ended(), 2)
+}
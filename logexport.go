@@ -0,0 +1,407 @@
+package scout
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// logBatchSize is the maximum number of logs exported per request.
+	logBatchSize = 512
+	// maxQueuedLogs bounds the logs waiting to be exported without a memory limit, newer logs are dropped
+	// once it is reached.
+	maxQueuedLogs = 8 * logBatchSize
+)
+
+// logExport is the exporter of the logs recorded with RecordLog, set while Scout is running and
+// exporting to an OTLP endpoint.
+var logExport atomic.Pointer[logExporter]
+
+// logExporter batches logs and exports them to the /v1/logs endpoint of an OTLP/HTTP receiver, as
+// protobuf payloads like zstdClient, since the OTLP logs exporters require a newer OpenTelemetry SDK.
+type logExporter struct {
+	// failover lists the URLs of the logs endpoints of the primary and failover OTLP endpoints
+	failover failover
+	headers  map[string]string
+	client   *http.Client
+	gzip     bool
+	resource *resourcepb.Resource
+	scope    *commonpb.InstrumentationScope
+	// filters are the span filters, applied to each log as if it were exported as a span event, so
+	// scrubbing and attribute filtering cover logs too
+	filters []spanFilter
+	// sampler samples the logs written outside of a trace, as it samples the spans they were exported
+	// on before logs were exported with the logs signal
+	sampler sdktrace.Sampler
+	stats   *exporterStats
+	// budget bounds the queue with the memory limit, shared with the queued spans
+	budget *memoryBudget
+
+	mu    sync.Mutex
+	queue []*logspb.LogRecord
+	// flushMu serializes flushes so logs are exported in order
+	flushMu sync.Mutex
+}
+
+func newLogExporter(endpoint string, res *resource.Resource, budget *memoryBudget) *logExporter {
+	conf := loadConfig()
+	var urls []string
+	for _, endpoint := range append([]string{endpoint}, conf.failoverEndpoints...) {
+		urls = append(urls, endpoint+"/v1/logs")
+	}
+	e := &logExporter{
+		failover: newFailover(urls),
+		headers:  otlpHeaders(),
+		client:   &http.Client{Timeout: exportRequestTimeout()},
+		gzip:     conf.compression != CompressionNone,
+		resource: &resourcepb.Resource{
+			Attributes: otlpAttributes(res.Attributes()),
+		},
+		scope: &commonpb.InstrumentationScope{
+			Name:    "github.com/scout-inc/scout-go",
+			Version: instrumentationVersion(),
+		},
+		filters: spanFilters(),
+		sampler: getSampler(),
+		stats:   &exporterStats{name: "otlp-logs", endpoint: endpoint, logs: true},
+		budget:  budget,
+	}
+	if budget != nil {
+		budget.register(e)
+	}
+	return e
+}
+
+// sampled reports whether a log written outside of a trace is exported, with the sampling rate of client
+// spans. Logs of a Scout request are sampled together, by the trace ID derived from the request ID.
+func (e *logExporter) sampled(ctx context.Context) bool {
+	var traceID trace.TraceID
+	if requestID := GetRequestID(ctx); requestID != "" {
+		traceID = requestTraceID(requestID)
+	} else {
+		binary.BigEndian.PutUint64(traceID[8:], rand.Uint64())
+	}
+	result := e.sampler.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: ctx,
+		TraceID:       traceID,
+		Name:          "scout.go.log",
+		Kind:          trace.SpanKindClient,
+	})
+	return result.Decision == sdktrace.RecordAndSample
+}
+
+// startLogExport exports the logs recorded with RecordLog with the logs signal, flushing them every
+// export batch timeout. It is called when Scout starts.
+func startLogExport(o *OTLP) {
+	conf := loadConfig()
+	if o == nil || o.logs == nil {
+		return
+	}
+	registerExporterStats(o.logs.stats)
+	logExport.Store(o.logs)
+	interval := time.Second
+	if conf.exportBatchTimeout > 0 {
+		interval = conf.exportBatchTimeout
+	}
+	startPeriodic(interval, func(ctx context.Context) {
+		_ = o.logs.flush(ctx)
+	})
+}
+
+// add queues the log, exporting the queue once it holds a full batch. The queue is bounded by the memory
+// limit if set, or by maxQueuedLogs.
+func (e *logExporter) add(ctx context.Context, r LogRecord) {
+	record := e.newRecord(ctx, r)
+	if record == nil {
+		return
+	}
+	e.stats.queued.Add(1)
+	if e.budget != nil && !e.budget.reserve(SignalLog, logSize(record)) {
+		e.stats.failed.Add(1)
+		logger.Debugf("dropping log, the memory limit is reached")
+		return
+	}
+	e.mu.Lock()
+	if e.budget == nil && len(e.queue) >= maxQueuedLogs {
+		e.mu.Unlock()
+		recordDrop(SignalLog)
+		e.stats.failed.Add(1)
+		logger.Debugf("dropping log, %d logs are waiting to be exported", maxQueuedLogs)
+		return
+	}
+	e.queue = append(e.queue, record)
+	full := len(e.queue) == logBatchSize
+	e.mu.Unlock()
+	if full {
+		go func() {
+			_ = e.flush(context.Background())
+		}()
+	}
+}
+
+// flush exports the queued logs in batches, returning the last error.
+func (e *logExporter) flush(ctx context.Context) error {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+	var lastErr error
+	for ctx.Err() == nil {
+		e.mu.Lock()
+		n := min(len(e.queue), logBatchSize)
+		batch := e.queue[:n:n]
+		e.queue = e.queue[n:]
+		if len(e.queue) == 0 {
+			e.queue = nil
+		}
+		e.mu.Unlock()
+		if n == 0 {
+			return lastErr
+		}
+		if e.budget != nil {
+			var size int64
+			for _, record := range batch {
+				size += logSize(record)
+			}
+			e.budget.release(size)
+		}
+		err := e.export(ctx, batch)
+		e.stats.mu.Lock()
+		e.stats.lastExport = time.Now()
+		e.stats.lastError = err
+		e.stats.mu.Unlock()
+		if err != nil {
+			e.stats.failed.Add(int64(n))
+			logger.Errorf("failed to export %d logs: %s", n, err)
+			lastErr = err
+			continue
+		}
+		e.stats.exported.Add(int64(n))
+	}
+	return ctx.Err()
+}
+
+// evictOldest drops the oldest queued log to make room for telemetry of higher priority.
+func (e *logExporter) evictOldest(signal Signal) (int64, bool) {
+	if signal != SignalLog {
+		return 0, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) == 0 {
+		return 0, false
+	}
+	record := e.queue[0]
+	e.queue = e.queue[1:]
+	e.stats.failed.Add(1)
+	return logSize(record), true
+}
+
+// logSize approximates the memory held by a queued log record.
+func logSize(record *logspb.LogRecord) int64 {
+	return int64(spanOverheadBytes + proto.Size(record))
+}
+
+func (e *logExporter) export(ctx context.Context, records []*logspb.LogRecord) error {
+	body, err := proto.Marshal(&collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: e.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      e.scope,
+				LogRecords: records,
+				SchemaUrl:  semconv.SchemaURL,
+			}},
+			SchemaUrl: semconv.SchemaURL,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling OTLP logs request: %w", err)
+	}
+	encoding := ""
+	if e.gzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err != nil {
+			return fmt.Errorf("compressing OTLP logs request: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("compressing OTLP logs request: %w", err)
+		}
+		body, encoding = buf.Bytes(), string(CompressionGzip)
+	}
+
+	return e.failover.send(func(i int) error {
+		return e.send(ctx, e.failover.endpoints[i], body, encoding)
+	})
+}
+
+// send posts the encoded logs request body to url.
+func (e *logExporter) send(ctx context.Context, url string, body []byte, encoding string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating OTLP logs request: %w", err)
+	}
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending OTLP logs request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("OTLP logs request failed: %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *logExporter) shutdown() {
+	logExport.CompareAndSwap(e, nil)
+	e.client.CloseIdleConnections()
+}
+
+// newRecord converts r to an OTLP log record correlated with the trace and span of ctx, or returns nil
+// if a span filter drops it.
+func (e *logExporter) newRecord(ctx context.Context, r LogRecord) *logspb.LogRecord {
+	sessionID, requestID, _ := validateRequest(ctx)
+	sc := trace.SpanContextFromContext(ctx)
+	traceID := sc.TraceID()
+	// spans of a Scout request are in the trace of the request ID, see StartTraceWithOptions
+	if requestID != "" {
+		traceID = requestTraceID(requestID)
+	}
+
+	var s sdktrace.ReadOnlySpan = tracetest.SpanStub{
+		Name:        "scout.go.log",
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: sc.SpanID(), TraceFlags: trace.FlagsSampled}),
+		SpanKind:    trace.SpanKindClient,
+		StartTime:   r.Time,
+		EndTime:     r.Time,
+		Attributes: []attribute.KeyValue{
+			attribute.String(ProjectIDAttribute, ProjectIDFromContext(ctx)),
+			attribute.String(SessionIDAttribute, sessionID),
+			attribute.String(RequestIDAttribute, requestID),
+		},
+		Events: []sdktrace.Event{{Name: LogEvent, Time: r.Time, Attributes: logEventAttributes(r)}},
+	}.Snapshot()
+	for _, filter := range e.filters {
+		if s = filter(s); s == nil {
+			return nil
+		}
+	}
+
+	record := &logspb.LogRecord{
+		TimeUnixNano:         uint64(r.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+		SeverityNumber:       severityNumber(r.Severity),
+		SeverityText:         r.Severity,
+	}
+	attrs := s.Attributes()
+	for _, event := range s.Events() {
+		for _, kv := range event.Attributes {
+			switch kv.Key {
+			case LogSeverityAttribute:
+			case LogMessageAttribute:
+				record.Body = otlpValue(kv.Value)
+			default:
+				attrs = append(attrs, kv)
+			}
+		}
+	}
+	record.Attributes = otlpAttributes(attrs)
+	if traceID.IsValid() {
+		record.TraceId = traceID[:]
+	}
+	if spanID := sc.SpanID(); spanID.IsValid() {
+		record.SpanId = spanID[:]
+		record.Flags = uint32(sc.TraceFlags())
+	}
+	return record
+}
+
+// severityNumber maps the severity of a log to its OTLP severity number.
+func severityNumber(severity string) logspb.SeverityNumber {
+	switch strings.ToUpper(severity) {
+	case "TRACE":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE
+	case "DEBUG":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case "INFO":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case "WARN", "WARNING":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case "ERROR":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	case "FATAL":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	case "PANIC":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL2
+	}
+	return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+}
+
+func otlpAttributes(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	kvs := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		kvs = append(kvs, &commonpb.KeyValue{Key: string(kv.Key), Value: otlpValue(kv.Value)})
+	}
+	return kvs
+}
+
+func otlpValue(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE:
+		var values []*commonpb.AnyValue
+		switch v.Type() {
+		case attribute.BOOLSLICE:
+			for _, b := range v.AsBoolSlice() {
+				values = append(values, otlpValue(attribute.BoolValue(b)))
+			}
+		case attribute.INT64SLICE:
+			for _, i := range v.AsInt64Slice() {
+				values = append(values, otlpValue(attribute.Int64Value(i)))
+			}
+		case attribute.FLOAT64SLICE:
+			for _, f := range v.AsFloat64Slice() {
+				values = append(values, otlpValue(attribute.Float64Value(f)))
+			}
+		default:
+			for _, s := range v.AsStringSlice() {
+				values = append(values, otlpValue(attribute.StringValue(s)))
+			}
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+}
@@ -0,0 +1,152 @@
+package scout
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// LeveledLogger is a Logger that also receives the SDK's warning, informational and debug messages.
+// A *logrus.Logger satisfies it, and NewSlogLogger adapts a *slog.Logger.
+type LeveledLogger interface {
+	Logger
+	Warnf(format string, v ...interface{})
+	Infof(format string, v ...interface{})
+	Debugf(format string, v ...interface{})
+}
+
+// LogLevelEnv selects the level of the default logger writing SDK diagnostics to stderr:
+// one of debug, info, warn or error. The default logger is silent when it is not set.
+const LogLevelEnv = "SCOUT_LOG_LEVEL"
+
+// LogLevel is the severity of an SDK diagnostic message.
+type LogLevel byte
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+	// LogLevelNone disables all messages
+	LogLevelNone
+)
+
+// ParseLogLevel parses a level name, as used by SCOUT_LOG_LEVEL.
+func ParseLogLevel(level string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	case "", "none", "off":
+		return LogLevelNone, nil
+	}
+	return LogLevelNone, fmt.Errorf("unknown log level %q", level)
+}
+
+// NewStderrLogger returns a logger writing messages of at least the given level to stderr.
+func NewStderrLogger(level LogLevel) LeveledLogger {
+	return stdLogger{level: level, l: log.New(os.Stderr, "scout: ", log.LstdFlags)}
+}
+
+type stdLogger struct {
+	level LogLevel
+	l     *log.Logger
+}
+
+func (s stdLogger) logf(level LogLevel, prefix, format string, v ...interface{}) {
+	if level < s.level {
+		return
+	}
+	s.l.Print(prefix + fmt.Sprintf(format, v...))
+}
+
+func (s stdLogger) Error(v ...interface{}) {
+	if LogLevelError < s.level {
+		return
+	}
+	s.l.Print("ERROR " + fmt.Sprint(v...))
+}
+
+func (s stdLogger) Errorf(format string, v ...interface{}) {
+	s.logf(LogLevelError, "ERROR ", format, v...)
+}
+
+func (s stdLogger) Warnf(format string, v ...interface{}) {
+	s.logf(LogLevelWarn, "WARN ", format, v...)
+}
+
+func (s stdLogger) Infof(format string, v ...interface{}) {
+	s.logf(LogLevelInfo, "INFO ", format, v...)
+}
+
+func (s stdLogger) Debugf(format string, v ...interface{}) {
+	s.logf(LogLevelDebug, "DEBUG ", format, v...)
+}
+
+// defaultLogger returns the logger configured by SCOUT_LOG_LEVEL.
+func defaultLogger() LeveledLogger {
+	level, err := ParseLogLevel(os.Getenv(LogLevelEnv))
+	if err != nil {
+		l := NewStderrLogger(LogLevelWarn)
+		l.Warnf("ignoring %s: %s", LogLevelEnv, err)
+		return l
+	}
+	if level == LogLevelNone {
+		return deadLog{}
+	}
+	return NewStderrLogger(level)
+}
+
+// NewSlogLogger adapts a *slog.Logger to receive the SDK's diagnostics.
+func NewSlogLogger(l *slog.Logger) LeveledLogger {
+	return slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) logf(level slog.Level, format string, v ...interface{}) {
+	ctx := context.Background()
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+	s.l.Log(ctx, level, fmt.Sprintf(format, v...))
+}
+
+func (s slogLogger) Error(v ...interface{}) {
+	s.l.Error(fmt.Sprint(v...))
+}
+
+func (s slogLogger) Errorf(format string, v ...interface{}) {
+	s.logf(slog.LevelError, format, v...)
+}
+
+func (s slogLogger) Warnf(format string, v ...interface{}) {
+	s.logf(slog.LevelWarn, format, v...)
+}
+
+func (s slogLogger) Infof(format string, v ...interface{}) {
+	s.logf(slog.LevelInfo, format, v...)
+}
+
+func (s slogLogger) Debugf(format string, v ...interface{}) {
+	s.logf(slog.LevelDebug, format, v...)
+}
+
+// errorLogger adapts a Logger that only supports errors, discarding lower level messages.
+type errorLogger struct {
+	Logger
+}
+
+func (errorLogger) Warnf(string, ...interface{})  {}
+func (errorLogger) Infof(string, ...interface{})  {}
+func (errorLogger) Debugf(string, ...interface{}) {}
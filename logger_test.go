@@ -0,0 +1,37 @@
+package scout
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		"debug":   LogLevelDebug,
+		"INFO":    LogLevelInfo,
+		"warning": LogLevelWarn,
+		"error":   LogLevelError,
+		"":        LogLevelNone,
+	}
+	for input, expected := range tests {
+		level, err := ParseLogLevel(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, level, input)
+	}
+	_, err := ParseLogLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestStderrLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := stdLogger{level: LogLevelWarn, l: log.New(&buf, "", 0)}
+	l.Debugf("debug")
+	l.Infof("info")
+	l.Warnf("warn %d", 1)
+	l.Errorf("error %d", 2)
+	assert.Equal(t, "WARN warn 1\nERROR error 2\n", buf.String())
+}
@@ -0,0 +1,197 @@
+package scout
+
+import (
+	"context"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithLogSpanEvents exports the logs recorded with RecordLog as the event of a span per log, as they were
+// before logs were exported with the OTLP logs signal, for backends that do not ingest OTLP logs yet.
+func WithLogSpanEvents() Option {
+	return option(func(conf *config) {
+		conf.logSpanEvents = true
+	})
+}
+
+// LogMode chooses how a log recorded with RecordLog is exported, so each integration can send its logs to
+// the backend with the retention they need. The integrations with logging libraries take the mode of their
+// logs with their WithLogMode option. LogModeDefault exports logs as log records with the OTLP logs signal,
+// or as span events with WithLogSpanEvents, and any mode falls back to span events when the OTLP exporter
+// is disabled.
+type LogMode byte
+
+const (
+	// LogModeDefault exports logs as log records, or as span events with WithLogSpanEvents.
+	LogModeDefault LogMode = iota
+	// LogModeRecords exports logs as log records with the OTLP logs signal. Logs are exported as span
+	// events instead when the OTLP exporter is disabled.
+	LogModeRecords
+	// LogModeEvents exports logs as span events, as with WithLogSpanEvents.
+	LogModeEvents
+	// LogModeBoth exports logs both as log records and as span events.
+	LogModeBoth
+)
+
+// LogTruncatedAttribute is set on the logs whose message or attribute values were cut, see WithLogTruncation.
+const LogTruncatedAttribute = "log.truncated"
+
+// WithLogTruncation cuts the messages of the logs recorded with RecordLog to maxMessageLength bytes, and
+// the string values of their attributes to maxAttributeLength bytes, so large payloads logged as fields
+// do not blow up the size of the exported logs. Truncated logs have the LogTruncatedAttribute attribute.
+// A length that is not positive leaves the message or the attribute values as they are.
+func WithLogTruncation(maxMessageLength, maxAttributeLength int) Option {
+	return runtimeOption(func(conf *config) {
+		conf.logMessageMaxLength = maxMessageLength
+		conf.logAttributeMaxLength = maxAttributeLength
+	})
+}
+
+// LogRecord is a log written by the application, exported with RecordLog.
+type LogRecord struct {
+	Time time.Time
+	// Severity is the level of the log, such as "WARN" or "ERROR".
+	Severity string
+	Message  string
+	// Attributes are the fields of the log.
+	Attributes []attribute.KeyValue
+	// Error marks a log reporting an error, which sets the error status of its span when logs are
+	// exported as span events.
+	Error bool
+	// ForceSample exports the log even if it is written within a trace that was not sampled.
+	ForceSample bool
+	// Mode chooses whether the log is exported as a log record, a span event or both.
+	Mode LogMode
+}
+
+// RecordLog exports a log written within the trace and the Scout session and request of ctx, for
+// integrations with logging libraries. Logs are exported with the OTLP logs signal, correlated with the
+// trace and span active in ctx, unless WithLogSpanEvents is set or the OTLP exporter is disabled, in
+// which case each log is exported as the event of a new span, or of a carrier span with WithLogBatching.
+// The Mode of r overrides this choice for the log.
+// Like the spans of a trace that was not sampled, logs written within it are dropped unless force sampled.
+// Logs written outside of a trace are sampled with the sampling rate of client spans, as their spans were.
+func RecordLog(ctx context.Context, r LogRecord) {
+	conf := loadConfig()
+	if !IsRunning() {
+		return
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	r = truncateLog(r, conf.logMessageMaxLength, conf.logAttributeMaxLength)
+	mode := r.Mode
+	if mode == LogModeDefault {
+		mode = LogModeRecords
+		if conf.logSpanEvents {
+			mode = LogModeEvents
+		}
+	}
+	exporter := logExport.Load()
+	if exporter == nil {
+		mode = LogModeEvents
+	}
+	if mode == LogModeRecords || mode == LogModeBoth {
+		recordLogRecord(ctx, exporter, r)
+	}
+	if mode == LogModeEvents || mode == LogModeBoth {
+		recordLogEvent(ctx, r)
+	}
+}
+
+// recordLogRecord exports the log as a log record, unless the trace it was written within was not sampled.
+func recordLogRecord(ctx context.Context, exporter *logExporter, r LogRecord) {
+	sc := trace.SpanContextFromContext(ctx)
+	forced := r.ForceSample || sc.TraceState().Get(forceSampleTraceStateKey) == forceSampleTraceStateValue
+	if sc.IsValid() && !sc.IsSampled() && !forced {
+		return
+	}
+	if !sc.IsValid() && !forced && !exporter.sampled(ctx) {
+		return
+	}
+	exporter.add(ctx, r)
+}
+
+// recordLogEvent exports the log as a span event, batched with WithLogBatching.
+func recordLogEvent(ctx context.Context, r LogRecord) {
+	conf := loadConfig()
+	if conf.logBatchInterval > 0 {
+		addLog(ctx, r)
+		return
+	}
+	recordLogSpanEvent(ctx, r)
+}
+
+// recordLogSpanEvent exports the log as the event of a new span.
+func recordLogSpanEvent(ctx context.Context, r LogRecord) {
+	opts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}
+	if r.ForceSample {
+		opts = append(opts, trace.WithAttributes(attribute.Bool(ForceSampleAttribute, true)))
+	}
+	span, _ := StartTraceWithTimestamp(ctx, "scout.go.log", r.Time, opts)
+	defer EndTrace(span)
+
+	// the span was sampled out, so any attributes would be discarded
+	if !span.IsRecording() {
+		return
+	}
+
+	span.AddEvent(LogEvent, trace.WithAttributes(logEventAttributes(r)...))
+
+	if r.Error {
+		span.SetStatus(codes.Error, r.Message)
+	}
+}
+
+// logEventAttributes returns the attributes of the log event of r.
+func logEventAttributes(r LogRecord) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2+len(r.Attributes))
+	attrs = append(attrs,
+		attribute.String(LogSeverityAttribute, r.Severity),
+		attribute.String(LogMessageAttribute, r.Message),
+	)
+	return append(attrs, r.Attributes...)
+}
+
+// truncateLog cuts the message and the string attribute values of r to the given lengths, marking it
+// with LogTruncatedAttribute if anything was cut. The attributes of r are copied rather than modified.
+func truncateLog(r LogRecord, maxMessageLength, maxAttributeLength int) LogRecord {
+	truncated := false
+	if maxMessageLength > 0 && len(r.Message) > maxMessageLength {
+		r.Message = truncateString(r.Message, maxMessageLength)
+		truncated = true
+	}
+	if maxAttributeLength > 0 {
+		var attrs []attribute.KeyValue
+		for i, kv := range r.Attributes {
+			if kv.Value.Type() != attribute.STRING || len(kv.Value.AsString()) <= maxAttributeLength {
+				continue
+			}
+			if attrs == nil {
+				attrs = slices.Clone(r.Attributes)
+			}
+			attrs[i].Value = attribute.StringValue(truncateString(kv.Value.AsString(), maxAttributeLength))
+		}
+		if attrs != nil {
+			r.Attributes = attrs
+			truncated = true
+		}
+	}
+	if truncated {
+		r.Attributes = append(r.Attributes[:len(r.Attributes):len(r.Attributes)], attribute.Bool(LogTruncatedAttribute, true))
+	}
+	return r
+}
+
+// truncateString cuts s to at most n bytes, without splitting a UTF-8 encoded rune.
+func truncateString(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,275 @@
+package scout
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRecordLogSpanEvent(t *testing.T) {
+	prev := loadState()
+	storeState(started)
+	defer storeState(prev)
+	recorder := recordSpans(t)
+
+	RecordLog(context.Background(), LogRecord{
+		Severity:   "ERROR",
+		Message:    "charge failed",
+		Attributes: []attribute.KeyValue{attribute.String("amount", "12")},
+		Error:      true,
+	})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "scout.go.log", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(LogSeverityAttribute, "ERROR"),
+		attribute.String(LogMessageAttribute, "charge failed"),
+		attribute.String("amount", "12"),
+	}, spans[0].Events()[0].Attributes)
+}
+
+func TestLogExporter(t *testing.T) {
+	prev := loadState()
+	storeState(idle)
+	defer storeState(prev)
+
+	requests := make(chan *collogspb.ExportLogsServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		req := &collogspb.ExportLogsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, req))
+		requests <- req
+	}))
+	defer server.Close()
+
+	e := newLogExporter(server.URL, resource.NewSchemaless(attribute.String("service.name", "api")), nil)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = ContextWithSessionID(ctx, "session")
+	now := time.Now()
+	e.add(ctx, LogRecord{Time: now, Severity: "WARN", Message: "slow charge", Attributes: []attribute.KeyValue{attribute.Int("amount", 12)}})
+	require.NoError(t, e.flush(context.Background()))
+
+	req := <-requests
+	require.Len(t, req.ResourceLogs, 1)
+	assert.Equal(t, "service.name", req.ResourceLogs[0].Resource.Attributes[0].Key)
+	records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, uint64(now.UnixNano()), record.TimeUnixNano)
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.SeverityNumber)
+	assert.Equal(t, "WARN", record.SeverityText)
+	assert.Equal(t, "slow charge", record.Body.GetStringValue())
+	assert.Equal(t, sc.TraceID().String(), trace.TraceID(record.TraceId).String())
+	assert.Equal(t, sc.SpanID().String(), trace.SpanID(record.SpanId).String())
+
+	attrs := map[string]string{}
+	for _, kv := range record.Attributes {
+		attrs[kv.Key] = kv.Value.String()
+	}
+	assert.Contains(t, attrs, SessionIDAttribute)
+	assert.Contains(t, attrs[SessionIDAttribute], "session")
+	assert.Contains(t, attrs["amount"], "12")
+	assert.NotContains(t, attrs, LogMessageAttribute)
+}
+
+func TestLogExporterFilters(t *testing.T) {
+	e := newLogExporter("http://localhost", resource.Empty(), nil)
+	e.filters = []spanFilter{func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		filtered := newFilteredSpan(s)
+		filtered.mapAttributes(func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+			if kv.Key == LogMessageAttribute {
+				kv.Value = attribute.StringValue("[REDACTED]")
+			}
+			return kv, true
+		})
+		return filtered
+	}}
+	record := e.newRecord(context.Background(), LogRecord{Severity: "INFO", Message: "token abc"})
+	assert.Equal(t, "[REDACTED]", record.Body.GetStringValue())
+
+	e.filters = append(e.filters, func(sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan { return nil })
+	assert.Nil(t, e.newRecord(context.Background(), LogRecord{Severity: "INFO", Message: "dropped"}))
+}
+
+func TestSeverityNumber(t *testing.T) {
+	for severity, want := range map[string]logspb.SeverityNumber{
+		"DEBUG":   logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG,
+		"warning": logspb.SeverityNumber_SEVERITY_NUMBER_WARN,
+		"FATAL":   logspb.SeverityNumber_SEVERITY_NUMBER_FATAL,
+		"PANIC":   logspb.SeverityNumber_SEVERITY_NUMBER_FATAL2,
+		"NOTICE":  logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED,
+	} {
+		assert.Equal(t, want, severityNumber(severity), severity)
+	}
+}
+
+func TestTruncateLog(t *testing.T) {
+	attrs := []attribute.KeyValue{attribute.String("body", "{\"a\":1}"), attribute.Int("status", 500), attribute.String("id", "7")}
+	r := truncateLog(LogRecord{Message: "héllo world", Attributes: attrs}, 2, 4)
+
+	assert.Equal(t, "h", r.Message)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("body", "{\"a\""),
+		attribute.Int("status", 500),
+		attribute.String("id", "7"),
+		attribute.Bool(LogTruncatedAttribute, true),
+	}, r.Attributes)
+	assert.Equal(t, attribute.String("body", "{\"a\":1}"), attrs[0])
+
+	r = truncateLog(LogRecord{Message: "ok", Attributes: attrs}, 2, 0)
+	assert.Equal(t, "ok", r.Message)
+	assert.Equal(t, attrs, r.Attributes)
+}
+
+func TestRecordLogSamplesUntracedLogs(t *testing.T) {
+	useConfig(t, &config{samplingRateMap: map[trace.SpanKind]float64{trace.SpanKindUnspecified: 1, trace.SpanKindClient: 0}})
+	prev := loadState()
+	storeState(started)
+	defer storeState(prev)
+	e := newLogExporter("http://localhost", resource.Empty(), nil)
+	logExport.Store(e)
+	defer logExport.Store(nil)
+
+	RecordLog(context.Background(), LogRecord{Severity: "INFO", Message: "dropped"})
+	RecordLog(context.Background(), LogRecord{Severity: "INFO", Message: "forced", ForceSample: true})
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled})
+	RecordLog(trace.ContextWithSpanContext(context.Background(), sc), LogRecord{Severity: "INFO", Message: "traced"})
+	require.Len(t, e.queue, 2)
+	assert.Equal(t, "forced", e.queue[0].Body.GetStringValue())
+	assert.Equal(t, "traced", e.queue[1].Body.GetStringValue())
+
+	e.sampler = sampler{traceIDUpperBounds: map[trace.SpanKind]uint64{trace.SpanKindClient: 1 << 62}}
+	request := ContextWithRequestID(context.Background(), "request")
+	first := e.sampled(request)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, e.sampled(request), "the logs of a request are sampled together")
+	}
+}
+
+func TestRecordLogModes(t *testing.T) {
+	useConfig(t, &config{})
+	prev := loadState()
+	storeState(started)
+	defer storeState(prev)
+	recorder := recordSpans(t)
+	e := newLogExporter("http://localhost", resource.Empty(), nil)
+	logExport.Store(e)
+	defer logExport.Store(nil)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	tests := []struct {
+		mode      LogMode
+		spanEvent bool
+		records   int
+		events    int
+	}{
+		{mode: LogModeDefault, records: 1},
+		{mode: LogModeRecords, records: 1},
+		{mode: LogModeEvents, events: 1},
+		{mode: LogModeBoth, records: 1, events: 1},
+		{mode: LogModeDefault, spanEvent: true, events: 1},
+		{mode: LogModeRecords, spanEvent: true, records: 1},
+	}
+	for _, tt := range tests {
+		updateConfig(func(next *config) { next.logSpanEvents = tt.spanEvent })
+		records, events := len(e.queue), len(recorder.Ended())
+		RecordLog(ctx, LogRecord{Severity: "INFO", Message: "charged", Mode: tt.mode})
+		assert.Equal(t, tt.records, len(e.queue)-records, "mode %d", tt.mode)
+		assert.Equal(t, tt.events, len(recorder.Ended())-events, "mode %d", tt.mode)
+	}
+
+	// logs are exported as span events when the OTLP exporter is disabled
+	logExport.Store(nil)
+	RecordLog(ctx, LogRecord{Severity: "INFO", Message: "charged", Mode: LogModeRecords})
+	assert.Len(t, recorder.Ended(), 4)
+}
+
+func TestLogExporterFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	e := newLogExporter(server.URL, resource.Empty(), nil)
+	e.add(context.Background(), LogRecord{Time: time.Now(), Severity: "WARN", Message: "slow charge"})
+	err := e.flush(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Equal(t, int64(1), e.stats.failed.Load())
+	_, lastErr := e.stats.lastResult()
+	assert.Equal(t, err, lastErr)
+
+	// logs are dropped once the queue is full
+	e.queue = make([]*logspb.LogRecord, maxQueuedLogs)
+	dropped := DroppedTelemetry()[SignalLog]
+	e.add(context.Background(), LogRecord{Time: time.Now(), Severity: "WARN", Message: "dropped"})
+	assert.Len(t, e.queue, maxQueuedLogs)
+	assert.Equal(t, int64(2), e.stats.failed.Load())
+	assert.Equal(t, dropped+1, DroppedTelemetry()[SignalLog])
+}
+
+func TestFlushTelemetryLogs(t *testing.T) {
+	useConfig(t, &config{})
+	t.Cleanup(resetExporterStats)
+	resetExporterStats()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	e := newLogExporter(server.URL, resource.Empty(), nil)
+	registerExporterStats(e.stats)
+	o := &OTLP{tracerProvider: sdktrace.NewTracerProvider(), ownsProvider: true, logs: e}
+
+	totals := exportTotals()
+	e.add(context.Background(), LogRecord{Time: time.Now(), Severity: "INFO", Message: "stopping"})
+	e.add(context.Background(), LogRecord{Time: time.Now(), Severity: "INFO", Message: "stopped"})
+	summary := flushTelemetry(context.Background(), o, time.Now(), totals)
+	assert.Equal(t, int64(2), summary.LogsFlushed)
+	assert.Zero(t, summary.LogsDropped)
+	assert.Zero(t, summary.SpansFlushed)
+	assert.NoError(t, summary.LastError)
+}
+
+func TestLogExporterFailover(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	var received atomic.Int64
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		received.Add(1)
+	}))
+	defer secondary.Close()
+	useConfig(t, &config{failoverEndpoints: []string{secondary.URL}})
+
+	e := newLogExporter(primary.URL, resource.Empty(), nil)
+	e.add(context.Background(), LogRecord{Time: time.Now(), Severity: "WARN", Message: "slow charge"})
+	require.NoError(t, e.flush(context.Background()))
+	assert.Equal(t, int64(1), received.Load())
+	assert.Equal(t, 1, e.failover.current())
+}
@@ -10,8 +10,29 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 )
 
+// Type is the kind of a metric, which decides how the backend aggregates it.
+type Type string
+
+const (
+	// TypeHistogram metrics are aggregated into a distribution of values.
+	TypeHistogram Type = "histogram"
+	// TypeCounter metrics are aggregated by summing their deltas.
+	TypeCounter Type = "counter"
+	// TypeGauge metrics are aggregated by keeping the last value.
+	TypeGauge Type = "gauge"
+)
+
 func shouldRecordMetric(rate float64) bool {
-	return rand.Float64() <= math.Min(rate, scout.GetMetricSamplingRate())
+	return rand.Float64() <= effectiveRate(rate)
+}
+
+func effectiveRate(rate float64) float64 {
+	return math.Min(rate, scout.GetMetricSamplingRate())
+}
+
+func record(ctx context.Context, metricType Type, name string, value float64, tags []attribute.KeyValue) {
+	tags = append(append(make([]attribute.KeyValue, 0, len(tags)+1), tags...), attribute.String(scout.MetricEventType, string(metricType)))
+	scout.RecordMetric(ctx, name, value, tags...)
 }
 
 // Histogram tracks the statistical distribution of a set of values for an event.
@@ -23,7 +44,7 @@ func Histogram(ctx context.Context, name string, value float64, tags []attribute
 	if !shouldRecordMetric(rate) {
 		return
 	}
-	scout.RecordMetric(ctx, name, value, tags...)
+	record(ctx, TypeHistogram, name, value, tags)
 }
 
 // Duration records duration information for an event (in seconds).
@@ -43,16 +64,29 @@ func Duration(ctx context.Context, name string, value time.Duration, tags []attr
 	if !shouldRecordMetric(rate) {
 		return
 	}
-	scout.RecordMetric(ctx, name, value.Seconds(), tags...)
+	record(ctx, TypeHistogram, name, value.Seconds(), tags)
 }
 
-// Count records a new metric instance with a value of 1.
-// Example (to increment the new_users counter -- i.e to record a new instance of new_user):
+// Count adds delta to a counter. Counters are aggregated by summing their deltas, and sampled deltas
+// are scaled up by the sampling rate so the sum stays accurate.
+// Example (to record a new instance of new_user):
 //
-// metric.Count(ctx, "new_users", nil, 1)
-func Count(ctx context.Context, name string, tags []attribute.KeyValue, rate float64) {
+// metric.Count(ctx, "new_users", 1, nil, 1)
+func Count(ctx context.Context, name string, delta float64, tags []attribute.KeyValue, rate float64) {
 	if !shouldRecordMetric(rate) {
 		return
 	}
-	scout.RecordMetric(ctx, name, 1, tags...)
+	if rate := effectiveRate(rate); rate > 0 && rate < 1 {
+		delta /= rate
+	}
+	record(ctx, TypeCounter, name, delta, tags)
+}
+
+// Gauge records the current value of a measurement, such as a queue depth.
+// Gauges are aggregated by keeping the last value, so they are never sampled.
+// Example:
+//
+// metric.Gauge(ctx, "queue.depth", float64(len(queue)), nil)
+func Gauge(ctx context.Context, name string, value float64, tags []attribute.KeyValue) {
+	record(ctx, TypeGauge, name, value, tags)
 }
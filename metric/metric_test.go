@@ -0,0 +1,78 @@
+package metric
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	// scout traces with the global provider until it is started
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	if err := scout.Reconfigure(scout.WithMetricSamplingRate(1)); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// recorded returns the metric event value and the type attribute of the last recorded metric span.
+func recorded(t *testing.T) (float64, string) {
+	spans := recorder.Ended()
+	if len(spans) == 0 {
+		t.Fatalf("[record] expected a metric span")
+	}
+	span := spans[len(spans)-1]
+	var value float64
+	for _, event := range span.Events() {
+		for _, kv := range event.Attributes {
+			if kv.Key == scout.MetricEventValue {
+				value = kv.Value.AsFloat64()
+			}
+		}
+	}
+	for _, kv := range span.Attributes() {
+		if kv.Key == scout.MetricEventType {
+			return value, kv.Value.AsString()
+		}
+	}
+	t.Fatalf("[record] expected a %s attribute, got %v", scout.MetricEventType, span.Attributes())
+	return 0, ""
+}
+
+func TestRecord(t *testing.T) {
+	tags := []attribute.KeyValue{attribute.String("queue", "jobs")}
+
+	Histogram(context.Background(), "jobs.duration", 1.5, tags, 1)
+	if value, typ := recorded(t); value != 1.5 || typ != string(TypeHistogram) {
+		t.Fatalf("[Histogram] expected 1.5 %s, got %v %s", TypeHistogram, value, typ)
+	}
+	Count(context.Background(), "jobs.processed", 2, tags, 1)
+	if value, typ := recorded(t); value != 2 || typ != string(TypeCounter) {
+		t.Fatalf("[Count] expected 2 %s, got %v %s", TypeCounter, value, typ)
+	}
+	Gauge(context.Background(), "jobs.depth", 3, tags)
+	if value, typ := recorded(t); value != 3 || typ != string(TypeGauge) {
+		t.Fatalf("[Gauge] expected 3 %s, got %v %s", TypeGauge, value, typ)
+	}
+	if len(tags) != 1 {
+		t.Fatalf("[record] expected the caller's tags to be left unchanged, got %v", tags)
+	}
+}
+
+func TestCountScalesSampledDeltas(t *testing.T) {
+	before := len(recorder.Ended())
+	for i := 0; i < 100 && len(recorder.Ended()) == before; i++ {
+		Count(context.Background(), "jobs.processed", 3, nil, 0.25)
+	}
+	if value, typ := recorded(t); value != 12 || typ != string(TypeCounter) {
+		t.Fatalf("[Count] expected the delta scaled to 12 %s, got %v %s", TypeCounter, value, typ)
+	}
+}
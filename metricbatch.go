@@ -0,0 +1,160 @@
+package scout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBatchedMetrics bounds the metrics buffered per carrier span, which is exported early once full,
+// by the number of events a span can hold.
+func maxBatchedMetrics() int {
+	return maxSpanEvents()
+}
+
+// maxSpanEvents returns the number of events a span can hold.
+func maxSpanEvents() int {
+	conf := loadConfig()
+	limits := sdktrace.NewSpanLimits()
+	if conf.spanLimits != nil {
+		limits = *conf.spanLimits
+	}
+	if limits.EventCountLimit < 0 {
+		return 1000
+	}
+	return max(limits.EventCountLimit, 1)
+}
+
+// WithMetricBatching buffers the metrics recorded with RecordMetric and the metric package, and exports
+// them every interval on one carrier span per session, request and parent span, rather than a span per
+// metric. The tags of batched metrics are set on their metric events rather than on the span.
+func WithMetricBatching(interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.metricBatchInterval = interval
+	})
+}
+
+type metricPoint struct {
+	name  string
+	value float64
+	t     time.Time
+	tags  []attribute.KeyValue
+}
+
+// batchKey groups metrics or logs sharing a carrier span.
+type batchKey struct {
+	projectID string
+	sessionID string
+	requestID string
+	traceID   trace.TraceID
+	spanID    trace.SpanID
+}
+
+type metricBatch struct {
+	// ctx carries the identifiers of the batch, detached from the context the metrics were recorded in
+	ctx    context.Context
+	points []metricPoint
+}
+
+var metricBatches struct {
+	mu      sync.Mutex
+	batches map[batchKey]*metricBatch
+}
+
+func startMetricBatching() {
+	conf := loadConfig()
+	if conf.metricBatchInterval <= 0 {
+		return
+	}
+	startPeriodic(conf.metricBatchInterval, func(context.Context) {
+		flushMetrics()
+	})
+	// flush the remaining metrics before the exporters shut down
+	onStopWorkers(flushMetrics)
+}
+
+func addMetric(ctx context.Context, name string, value float64, t time.Time, tags []attribute.KeyValue) {
+	key := newBatchKey(ctx)
+	point := metricPoint{name: name, value: value, t: t, tags: tags}
+
+	metricBatches.mu.Lock()
+	if metricBatches.batches == nil {
+		metricBatches.batches = map[batchKey]*metricBatch{}
+	}
+	batch, ok := metricBatches.batches[key]
+	if !ok {
+		batch = &metricBatch{ctx: batchContext(ctx)}
+		metricBatches.batches[key] = batch
+	}
+	batch.points = append(batch.points, point)
+	full := len(batch.points) >= maxBatchedMetrics()
+	if full {
+		delete(metricBatches.batches, key)
+	}
+	metricBatches.mu.Unlock()
+
+	if full {
+		batch.export()
+	}
+}
+
+func newBatchKey(ctx context.Context) batchKey {
+	parent := trace.SpanContextFromContext(ctx)
+	return batchKey{
+		projectID: ProjectIDFromContext(ctx),
+		sessionID: GetSessionID(ctx),
+		requestID: GetRequestID(ctx),
+		traceID:   parent.TraceID(),
+		spanID:    parent.SpanID(),
+	}
+}
+
+// batchContext returns the context of the carrier span of a batch, with the identifiers and the parent
+// span of ctx, detached from the cancellation and values of ctx.
+func batchContext(ctx context.Context) context.Context {
+	detached := CopyRequestIDs(context.Background(), ctx)
+	if projectID, ok := ctx.Value(ContextKeys.ProjectID).(string); ok {
+		detached = WithProjectIDOverride(detached, projectID)
+	}
+	return trace.ContextWithSpanContext(detached, trace.SpanContextFromContext(ctx))
+}
+
+// flushMetrics exports the buffered metrics.
+func flushMetrics() {
+	metricBatches.mu.Lock()
+	batches := metricBatches.batches
+	metricBatches.batches = nil
+	metricBatches.mu.Unlock()
+	for _, batch := range batches {
+		batch.export()
+	}
+}
+
+func (b *metricBatch) export() {
+	start, end := b.points[0].t, b.points[0].t
+	for _, point := range b.points {
+		if point.t.Before(start) {
+			start = point.t
+		}
+		if point.t.After(end) {
+			end = point.t
+		}
+	}
+	span, _ := StartTraceWithTimestamp(b.ctx, ScopedKey("metric", ptr.String("-")), start, []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)})
+	defer span.End(trace.WithTimestamp(end))
+	if !span.IsRecording() {
+		return
+	}
+	for _, point := range b.points {
+		attrs := append([]attribute.KeyValue{
+			attribute.String(MetricEventName, point.name),
+			attribute.Float64(MetricEventValue, point.value),
+		}, point.tags...)
+		span.AddEvent(MetricEvent, trace.WithAttributes(attrs...), trace.WithTimestamp(point.t))
+	}
+}
@@ -0,0 +1,55 @@
+package scout
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestMetricBatching(t *testing.T) {
+	recorder := recordSpans(t)
+	// spans are only attributed to sessions until Scout is stopped
+	prev := loadState()
+	storeState(idle)
+	defer storeState(prev)
+
+	request := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "request")
+	now := time.Now()
+	addMetric(request, "queries", 1, now, nil)
+	addMetric(request, "queries", 2, now.Add(time.Second), []attribute.KeyValue{attribute.String("table", "users")})
+	addMetric(context.Background(), "queue.depth", 3, now, nil)
+	flushMetrics()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		if !slices.Contains(span.Attributes(), attribute.String(SessionIDAttribute, "session")) {
+			assert.Len(t, span.Events(), 1)
+			continue
+		}
+		require.Len(t, span.Events(), 2)
+		assert.Contains(t, span.Events()[1].Attributes, attribute.String("table", "users"))
+		assert.Equal(t, now, span.StartTime())
+		assert.Equal(t, now.Add(time.Second), span.EndTime())
+	}
+
+	flushMetrics()
+	assert.Len(t, recorder.Ended(), 2)
+}
+
+func TestMetricBatchingExportsFullBatches(t *testing.T) {
+	recorder := recordSpans(t)
+
+	for i := 0; i < maxBatchedMetrics()+1; i++ {
+		addMetric(context.Background(), "jobs", 1, time.Now(), nil)
+	}
+	require.Len(t, recorder.Ended(), 1)
+	assert.Len(t, recorder.Ended()[0].Events(), maxBatchedMetrics())
+	flushMetrics()
+	assert.Len(t, recorder.Ended(), 2)
+}
@@ -0,0 +1,69 @@
+package scout
+
+import (
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// MaxMetricNameLength is the longest metric name accepted by RecordMetric.
+	MaxMetricNameLength = 255
+	// MaxMetricTagKeyLength is the longest metric tag key accepted by RecordMetric.
+	MaxMetricTagKeyLength = 128
+)
+
+// metricNamePattern matches valid metric names and tag keys: a letter followed by letters, digits,
+// underscores, dots, dashes and slashes.
+var metricNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_./-]*$`)
+
+// WithMetricNameLowercasing lowercases metric names and tag keys when they are recorded, so metrics
+// recorded as "Queue.Depth" and "queue.depth" are not split into two series.
+func WithMetricNameLowercasing() Option {
+	return runtimeOption(func(conf *config) {
+		conf.lowercaseMetricNames = true
+	})
+}
+
+func validMetricName(name string, maxLength int) bool {
+	return len(name) <= maxLength && metricNamePattern.MatchString(name)
+}
+
+// normalizeMetric lowercases the name and tag keys of a metric if WithMetricNameLowercasing is set, and
+// validates them. Metrics with an invalid name are rejected and tags with an invalid key are dropped,
+// logging why at debug level, as the backend would otherwise drop them silently.
+func normalizeMetric(name string, tags []attribute.KeyValue) (string, []attribute.KeyValue, bool) {
+	conf := loadConfig()
+	if conf.lowercaseMetricNames {
+		name = strings.ToLower(name)
+	}
+	if !validMetricName(name, MaxMetricNameLength) {
+		logger.Debugf("dropping metric with invalid name %q: names start with a letter, are made of letters, digits, '_', '.', '-' and '/', and are at most %d characters", name, MaxMetricNameLength)
+		return "", nil, false
+	}
+	changed := false
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = string(tag.Key)
+		if conf.lowercaseMetricNames {
+			keys[i] = strings.ToLower(keys[i])
+		}
+		if keys[i] != string(tag.Key) || !validMetricName(keys[i], MaxMetricTagKeyLength) {
+			changed = true
+		}
+	}
+	if !changed {
+		return name, tags, true
+	}
+	// the caller's tags are left unchanged
+	valid := make([]attribute.KeyValue, 0, len(tags))
+	for i, tag := range tags {
+		if !validMetricName(keys[i], MaxMetricTagKeyLength) {
+			logger.Debugf("dropping tag with invalid key %q of metric %q: keys start with a letter, are made of letters, digits, '_', '.', '-' and '/', and are at most %d characters", keys[i], name, MaxMetricTagKeyLength)
+			continue
+		}
+		valid = append(valid, attribute.KeyValue{Key: attribute.Key(keys[i]), Value: tag.Value})
+	}
+	return name, valid, true
+}
@@ -0,0 +1,54 @@
+package scout
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestNormalizeMetric(t *testing.T) {
+	useConfig(t, &config{})
+
+	tests := map[string]struct {
+		name      string
+		tags      []attribute.KeyValue
+		lowercase bool
+		wantName  string
+		wantTags  []attribute.KeyValue
+		wantOK    bool
+	}{
+		"valid":           {name: "db.query/latency_ms", tags: []attribute.KeyValue{attribute.String("table", "users")}, wantName: "db.query/latency_ms", wantTags: []attribute.KeyValue{attribute.String("table", "users")}, wantOK: true},
+		"leading digit":   {name: "1queries"},
+		"spaces":          {name: "db query"},
+		"empty":           {name: ""},
+		"too long":        {name: strings.Repeat("a", MaxMetricNameLength+1)},
+		"invalid tag key": {name: "queries", tags: []attribute.KeyValue{attribute.String("table name", "users"), attribute.Bool("cached", true)}, wantName: "queries", wantTags: []attribute.KeyValue{attribute.Bool("cached", true)}, wantOK: true},
+		"lowercased":      {name: "Queue.Depth", tags: []attribute.KeyValue{attribute.String("Queue", "jobs")}, lowercase: true, wantName: "queue.depth", wantTags: []attribute.KeyValue{attribute.String("queue", "jobs")}, wantOK: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			updateConfig(func(c *config) { c.lowercaseMetricNames = tt.lowercase })
+			tags := append([]attribute.KeyValue(nil), tt.tags...)
+			gotName, gotTags, ok := normalizeMetric(tt.name, tags)
+			if ok != tt.wantOK || gotName != tt.wantName || len(gotTags) != len(tt.wantTags) {
+				t.Fatalf("[normalizeMetric] expected %q %v %t, got %q %v %t", tt.wantName, tt.wantTags, tt.wantOK, gotName, gotTags, ok)
+			}
+			for i := range gotTags {
+				if gotTags[i] != tt.wantTags[i] || len(tt.tags) > 0 && tags[0] != tt.tags[0] {
+					t.Fatalf("[normalizeMetric] expected tags %v, got %v", tt.wantTags, gotTags)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordMetricRejectsInvalidNames(t *testing.T) {
+	recorder := recordSpans(t)
+	RecordMetric(context.Background(), "db query", 1)
+	RecordMetric(context.Background(), "db.query", 1)
+	if len(recorder.Ended()) != 1 {
+		t.Fatalf("[RecordMetric] expected only the valid metric to be recorded, got %d spans", len(recorder.Ended()))
+	}
+}
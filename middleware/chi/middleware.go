@@ -3,10 +3,12 @@ package chi
 import (
 	"net/http"
 
+	gochi "github.com/go-chi/chi/v5"
 	"github.com/scout-inc/scout-go"
 	"github.com/scout-inc/scout-go/middleware"
 
 	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
 // chi-compatible middleware
@@ -14,15 +16,34 @@ func Middleware(next http.Handler) http.Handler {
 	middleware.AssertScoutIsRunning()
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		ctx := scout.InterceptRequest(r)
+		if !scout.IntegrationEnabled(scout.IntegrationChi) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := scout.ContextWithRequestAttributes(scout.InterceptRequest(r))
+		middleware.SetRequestIDHeader(ctx, w.Header())
 		span, ctx := scout.StartTrace(ctx, scout.ScopedKey("chi", nil))
 		defer scout.EndTrace(span)
 
 		r = r.WithContext(ctx)
-		next.ServeHTTP(w, r)
+		rec := middleware.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r)
 
 		span.SetAttributes(attribute.String(scout.SourceAttribute, "GoChiMiddleware"))
 		span.SetAttributes(middleware.GetRequestAttributes(r)...)
+		span.SetAttributes(scout.ResponseHeaderAttributes(w.Header())...)
+		span.SetAttributes(scout.RequestAttributes(ctx)...)
+		span.SetAttributes(semconv.HTTPStatusCode(rec.Status()))
+		route := r.URL.Path
+		// the route pattern is only known once chi has routed the request
+		if rctx := gochi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(semconv.HTTPRoute(pattern))
+			}
+		}
+		middleware.SetSpanStatus(span, route, rec.Status())
 	}
 	return http.HandlerFunc(fn)
 }
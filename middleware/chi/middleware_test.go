@@ -0,0 +1,134 @@
+package chi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	gochi "github.com/go-chi/chi/v5"
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// exportSpans starts Scout with opts, exporting to a collector, and returns a function stopping Scout and
+// returning the spans exported by then.
+func exportSpans(t *testing.T, opts ...scout.Option) func() []*tracepb.Span {
+	var mu sync.Mutex
+	var spans []*tracepb.Span
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := &coltracepb.ExportTraceServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, req))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}))
+	t.Cleanup(collector.Close)
+
+	scout.SetOtelEndpoint(collector.URL)
+	scout.Start(append([]scout.Option{scout.WithSamplingRate(1), scout.WithCompression(scout.CompressionNone)}, opts...)...)
+	return func() []*tracepb.Span {
+		scout.Stop()
+		mu.Lock()
+		defer mu.Unlock()
+		return spans
+	}
+}
+
+// get serves a GET request for path with router, returning the response status.
+func get(t *testing.T, router http.Handler, path string) int {
+	server := httptest.NewServer(router)
+	defer server.Close()
+	res, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	return res.StatusCode
+}
+
+// findSpan returns the span named name, with its string and int attributes.
+func findSpan(t *testing.T, spans []*tracepb.Span, name string) (*tracepb.Span, map[string]any) {
+	for _, s := range spans {
+		if s.Name != name {
+			continue
+		}
+		attrs := map[string]any{}
+		for _, kv := range s.Attributes {
+			if v, ok := kv.Value.Value.(*commonpb.AnyValue_IntValue); ok {
+				attrs[kv.Key] = v.IntValue
+				continue
+			}
+			attrs[kv.Key] = kv.Value.GetStringValue()
+		}
+		return s, attrs
+	}
+	t.Fatalf("no span named %s was exported", name)
+	return nil, nil
+}
+
+func TestMiddlewareRouteAndStatus(t *testing.T) {
+	stop := exportSpans(t)
+	router := gochi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, router, "/users/42"))
+
+	span, attrs := findSpan(t, stop(), "GET /users/{id}")
+	assert.Equal(t, "/users/{id}", attrs["http.route"])
+	assert.Equal(t, int64(http.StatusServiceUnavailable), attrs["http.status_code"])
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, span.Status.Code)
+}
+
+func TestMiddlewareEnrichesRoutedSpans(t *testing.T) {
+	stop := exportSpans(t, scout.WithSpanEnrichment(scout.MatchSpanName(regexp.MustCompile(`^GET /api/payments/`)), attribute.String("team", "payments")))
+	router := gochi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/api/payments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	assert.Equal(t, http.StatusAccepted, get(t, router, "/api/payments/42"))
+
+	_, attrs := findSpan(t, stop(), "GET /api/payments/{id}")
+	assert.Equal(t, "payments", attrs["team"])
+	assert.Equal(t, "/api/payments/{id}", attrs["http.route"])
+	assert.NotContains(t, attrs, "scout.enrichment.rules")
+}
+
+func TestMiddlewareHijack(t *testing.T) {
+	router := gochi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		require.True(t, ok, "the middleware keeps the writer hijackable")
+		conn, rw, err := hijacker.Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		_ = rw.Flush()
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/ws")
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+}
@@ -1,38 +1,52 @@
 package echo
 
 import (
-	"context"
+	"errors"
+	"fmt"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/scout-inc/scout-go"
 	"github.com/scout-inc/scout-go/middleware"
 	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // echo-compatible middlware
+//
+// Spans are named after the route path, such as "GET /users/:id". The status code of a returned error is
+// the one echo's HTTPErrorHandler responds with, and panics are recorded on the span before being passed
+// on to echo's Recover middleware, which must be registered before this middleware to handle them.
 func Middleware() echo.MiddlewareFunc {
 	middleware.AssertScoutIsRunning()
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			ctx := c.Request().Context()
-
-			requestDetails := c.Request().Header.Get(scout.RequestTracerHeader)
-			sessionSecureId, requestId, err := scout.ExtractIdsFromRequest(requestDetails)
-			if err == nil {
-				ctx = context.WithValue(ctx, scout.ContextKeys.SessionSecureID, sessionSecureId)
-				ctx = context.WithValue(ctx, scout.ContextKeys.RequestID, requestId)
+			if !scout.IntegrationEnabled(scout.IntegrationEcho) {
+				return next(c)
 			}
+			ctx := scout.ContextWithRequestAttributes(scout.InterceptRequest(c.Request()))
+			middleware.SetRequestIDHeader(ctx, c.Response().Header())
 
 			span, scoutContext := scout.StartTrace(ctx, scout.ScopedKey("echo", nil))
 			defer scout.EndTrace(span)
+			defer func() {
+				if r := recover(); r != nil {
+					err, ok := r.(error)
+					if !ok {
+						err = fmt.Errorf("panic: %+v", r)
+					}
+					setRequestAttributes(span, c, http.StatusInternalServerError)
+					scout.RecordSpanError(span, err)
+					panic(r)
+				}
+			}()
 
 			c.SetRequest(c.Request().WithContext(scoutContext))
-			err = next(c)
-
-			span.SetAttributes(attribute.String(scout.SourceAttribute, "GoEchoMiddleware"))
-			span.SetAttributes(middleware.GetRequestAttributes(c.Request())...)
+			err := next(c)
 
+			setRequestAttributes(span, c, statusCode(c, err))
 			if err != nil {
 				scout.RecordSpanError(span, err)
 			}
@@ -41,3 +55,29 @@ func Middleware() echo.MiddlewareFunc {
 		}
 	}
 }
+
+func setRequestAttributes(span trace.Span, c echo.Context, status int) {
+	span.SetAttributes(attribute.String(scout.SourceAttribute, "GoEchoMiddleware"))
+	span.SetAttributes(middleware.GetRequestAttributes(c.Request())...)
+	if path := c.Path(); path != "" {
+		span.SetName(c.Request().Method + " " + path)
+		span.SetAttributes(semconv.HTTPRoute(path))
+	}
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	middleware.SetSpanStatus(span, c.Path(), status)
+	span.SetAttributes(scout.ResponseHeaderAttributes(c.Response().Header())...)
+	span.SetAttributes(scout.RequestAttributes(c.Request().Context())...)
+}
+
+// statusCode returns the status code of the response, which for a returned error is only written
+// once echo's HTTPErrorHandler handles it after the middleware chain returns.
+func statusCode(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,55 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	scout.Start(scout.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), scout.WithoutOTLPExporter())
+	code := m.Run()
+	scout.Stop()
+	os.Exit(code)
+}
+
+// span returns the last ended span named name.
+func span(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	spans := recorder.Ended()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name() == name {
+			return spans[i]
+		}
+	}
+	t.Fatalf("no span named %s in %d spans", name, len(spans))
+	return nil
+}
+
+func TestMiddlewareHTTPError(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/coffee/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusTeapot, "no coffee")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coffee/1", nil))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+
+	s := span(t, "GET /coffee/:id")
+	assert.Contains(t, s.Attributes(), semconv.HTTPStatusCode(http.StatusTeapot))
+	assert.Contains(t, s.Attributes(), semconv.HTTPRoute("/coffee/:id"))
+	require.NotEmpty(t, s.Events())
+	assert.Equal(t, semconv.ExceptionEventName, s.Events()[0].Name)
+}
@@ -1,6 +1,10 @@
 package fiber
 
 import (
+	"context"
+	"net/http"
+	"net/url"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/scout-inc/scout-go"
 	"github.com/scout-inc/scout-go/middleware"
@@ -8,35 +12,83 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
-// fiber-compatible middleware
+// Middleware traces fiber requests. The session and request IDs are set on the user context, so spans
+// started from c.UserContext() in handlers are attributed to the session, and are copied to the user
+// values of the fasthttp context for handlers passing c.Context() instead.
 func Middleware() fiber.Handler {
 	middleware.AssertScoutIsRunning()
 
 	return func(c *fiber.Ctx) error {
-		ctx := c.Context()
-
-		requestDetails := string(c.Request().Header.Peek(scout.RequestTracerHeader))
-		sessionSecureId, requestId, err := scout.ExtractIdsFromRequest(requestDetails)
-		if err == nil {
-			ctx.SetUserValue(scout.ContextKeys.SessionSecureID, sessionSecureId)
-			ctx.SetUserValue(scout.ContextKeys.RequestID, requestId)
+		if !scout.IntegrationEnabled(scout.IntegrationFiber) {
+			return c.Next()
+		}
+		ctx := scout.InterceptRequestHeader(c.UserContext(), string(c.Request().Header.Peek(scout.RequestTracerHeader)))
+		ctx = scout.ContextWithRequestAttributes(ctx)
+		setUserValues(c, ctx)
+		if id := scout.GeneratedRequestID(ctx); id != "" {
+			c.Set(scout.RequestIDResponseHeader, id)
 		}
 
 		span, scoutContext := scout.StartTrace(ctx, scout.ScopedKey("fiber", nil))
 		defer scout.EndTrace(span)
 
 		c.SetUserContext(scoutContext)
-		err = c.Next()
+		err := c.Next()
 
 		scout.RecordSpanError(
 			span, err,
 			attribute.String(scout.SourceAttribute, "GoFiberMiddleware"),
-			attribute.String(string(semconv.HTTPURLKey), c.OriginalURL()),
+			attribute.String(string(semconv.HTTPURLKey), redactedURL(c.OriginalURL())),
 			attribute.String(string(semconv.HTTPRouteKey), c.Path()),
 			attribute.String(string(semconv.HTTPMethodKey), c.Method()),
 			attribute.String(string(semconv.HTTPClientIPKey), c.IP()),
 			attribute.Int(string(semconv.HTTPStatusCodeKey), c.Response().StatusCode()),
 		)
+		span.SetAttributes(scout.RequestHeaderAttributes(requestHeader(c))...)
+		span.SetAttributes(scout.ResponseHeaderAttributes(responseHeader(c))...)
+		span.SetAttributes(scout.RequestAttributes(ctx)...)
+		middleware.SetSpanStatus(span, c.Route().Path, c.Response().StatusCode())
 		return err
 	}
 }
+
+// UserContext returns the user context of c carrying the Scout session and request IDs, even if a
+// middleware replaced the user context after the Scout middleware ran.
+func UserContext(c *fiber.Ctx) context.Context {
+	return scout.CopyRequestIDs(c.UserContext(), c.Context())
+}
+
+// setUserValues stores the session and request IDs of ctx on the fasthttp context, which
+// implements context.Context by looking up its user values.
+func setUserValues(c *fiber.Ctx, ctx context.Context) {
+	if requestID := scout.GetRequestID(ctx); requestID != "" {
+		c.Context().SetUserValue(scout.ContextKeys.SessionSecureID, scout.GetSessionID(ctx))
+		c.Context().SetUserValue(scout.ContextKeys.RequestID, requestID)
+	}
+}
+
+// requestHeader copies the request headers, as fasthttp reuses their memory once the request completes.
+func requestHeader(c *fiber.Ctx) http.Header {
+	h := http.Header{}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		h.Add(string(key), string(value))
+	})
+	return h
+}
+
+// responseHeader copies the response headers, as fasthttp reuses their memory once the request completes.
+func responseHeader(c *fiber.Ctx) http.Header {
+	h := http.Header{}
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		h.Add(string(key), string(value))
+	})
+	return h
+}
+
+func redactedURL(rawURL string) string {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return scout.RedactURL(u).String()
+}
@@ -0,0 +1,67 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	scout.Start(scout.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), scout.WithoutOTLPExporter())
+	code := m.Run()
+	scout.Stop()
+	os.Exit(code)
+}
+
+// span returns the last ended span with the attribute kv.
+func span(t *testing.T, kv attribute.KeyValue) sdktrace.ReadOnlySpan {
+	spans := recorder.Ended()
+	for i := len(spans) - 1; i >= 0; i-- {
+		for _, attr := range spans[i].Attributes() {
+			if attr == kv {
+				return spans[i]
+			}
+		}
+	}
+	t.Fatalf("no span with %s in %d spans", kv.Key, len(spans))
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/charges/:id", func(c *fiber.Ctx) error {
+		userSpan, _ := scout.StartTrace(c.UserContext(), "user-context", attribute.String("handler", "user-context"))
+		scout.EndTrace(userSpan)
+		fasthttpSpan, _ := scout.StartTrace(c.Context(), "fasthttp-context", attribute.String("handler", "fasthttp-context"))
+		scout.EndTrace(fasthttpSpan)
+		return c.SendStatus(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/charges/42", nil)
+	req.Header.Set(scout.RequestTracerHeader, "session/cmVxdWVzdA==")
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+
+	for _, handler := range []string{"user-context", "fasthttp-context"} {
+		attrs := span(t, attribute.String("handler", handler)).Attributes()
+		assert.Contains(t, attrs, attribute.String(scout.SessionIDAttribute, "session"), handler)
+		assert.Contains(t, attrs, attribute.String(scout.RequestIDAttribute, "cmVxdWVzdA=="), handler)
+	}
+	requestSpan := span(t, attribute.String(scout.SourceAttribute, "GoFiberMiddleware"))
+	assert.Contains(t, requestSpan.Attributes(), semconv.HTTPStatusCode(http.StatusServiceUnavailable))
+	assert.Contains(t, requestSpan.Attributes(), attribute.String(scout.SessionIDAttribute, "session"))
+}
@@ -3,6 +3,7 @@ package gin
 import (
 	"github.com/scout-inc/scout-go"
 	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,24 +15,40 @@ func Middleware() gin.HandlerFunc {
 	middleware.AssertScoutIsRunning()
 
 	return func(c *gin.Context) {
-		requestDetails := c.GetHeader(scout.RequestTracerHeader)
-		secureSessionId, requestId, err := scout.ExtractIdsFromRequest(requestDetails)
-		if err != nil {
+		if !scout.IntegrationEnabled(scout.IntegrationGin) {
 			return
 		}
+		ctx := scout.ContextWithRequestAttributes(scout.InterceptRequest(c.Request))
+		requestId := scout.GetRequestID(ctx)
+		if requestId == "" {
+			return
+		}
+		middleware.SetRequestIDHeader(ctx, c.Writer.Header())
 
-		c.Set(string(scout.ContextKeys.SessionSecureID), secureSessionId)
+		// gin.Context only resolves string keys, so keep them for handlers passing it as a context
+		c.Set(string(scout.ContextKeys.SessionSecureID), scout.GetSessionID(ctx))
 		c.Set(string(scout.ContextKeys.RequestID), requestId)
 
-		span, _ := scout.StartTrace(c, scout.ScopedKey("gin", nil))
+		span, ctx := scout.StartTrace(ctx, scout.ScopedKey("gin", nil))
 		defer scout.EndTrace(span)
 
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
 
 		span.SetAttributes(attribute.String(scout.SourceAttribute, "GoGinMiddleware"))
 		span.SetAttributes(middleware.GetRequestAttributes(c.Request)...)
-		if len(c.Errors) > 0 {
-			scout.RecordSpanError(span, c.Errors[0])
+		span.SetAttributes(semconv.HTTPStatusCode(c.Writer.Status()))
+		middleware.SetSpanStatus(span, c.FullPath(), c.Writer.Status())
+		span.SetAttributes(scout.ResponseHeaderAttributes(c.Writer.Header())...)
+		span.SetAttributes(scout.RequestAttributes(ctx)...)
+		if route := c.FullPath(); route != "" {
+			span.SetName(c.Request.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		// each error added with c.Error is recorded as its own exception event
+		for _, err := range c.Errors {
+			scout.RecordSpanError(span, err.Err)
 		}
 	}
 }
@@ -0,0 +1,64 @@
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	scout.Start(scout.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), scout.WithoutOTLPExporter())
+	code := m.Run()
+	scout.Stop()
+	os.Exit(code)
+}
+
+func TestMiddlewareErrors(t *testing.T) {
+	router := gin.New()
+	router.Use(Middleware())
+	router.POST("/invoices/:id", func(c *gin.Context) {
+		_ = c.Error(errors.New("tax lookup failed"))
+		_ = c.Error(errors.New("pdf rendering failed"))
+		c.Status(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/invoices/1", nil)
+	req.Header.Set(scout.RequestTracerHeader, "session/request")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "POST /invoices/:id" {
+			span = s
+		}
+	}
+	require.NotNil(t, span)
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCode(http.StatusBadGateway))
+	var messages []string
+	for _, event := range span.Events() {
+		if event.Name != semconv.ExceptionEventName {
+			continue
+		}
+		for _, kv := range event.Attributes {
+			if kv.Key == semconv.ExceptionMessageKey {
+				messages = append(messages, kv.Value.AsString())
+			}
+		}
+	}
+	assert.Equal(t, []string{"tax lookup failed", "pdf rendering failed"}, messages)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/scout-inc/scout-go/middleware"
 	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 
 	"github.com/scout-inc/scout-go"
 )
@@ -14,17 +15,27 @@ func Middleware(next http.Handler) http.Handler {
 	middleware.AssertScoutIsRunning()
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		ctx := scout.InterceptRequest(r)
+		if !scout.IntegrationEnabled(scout.IntegrationGorillaMux) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := scout.ContextWithRequestAttributes(scout.InterceptRequest(r))
+		middleware.SetRequestIDHeader(ctx, w.Header())
 		r = r.WithContext(ctx)
 
 		span, ctx := scout.StartTrace(ctx, scout.ScopedKey("gorillamux", nil))
 		defer scout.EndTrace(span)
 
 		r = r.WithContext(ctx)
-		next.ServeHTTP(w, r)
+		rec := middleware.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r)
 
 		span.SetAttributes(attribute.String(scout.SourceAttribute, "GoGorillaMuxMiddleware"))
 		span.SetAttributes(middleware.GetRequestAttributes(r)...)
+		span.SetAttributes(scout.ResponseHeaderAttributes(w.Header())...)
+		span.SetAttributes(scout.RequestAttributes(ctx)...)
+		span.SetAttributes(semconv.HTTPStatusCode(rec.Status()))
+		middleware.SetSpanStatus(span, r.URL.Path, rec.Status())
 	}
 	return http.HandlerFunc(fn)
 }
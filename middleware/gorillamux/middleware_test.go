@@ -0,0 +1,47 @@
+package gorillamux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	scout.Start(scout.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), scout.WithoutOTLPExporter())
+	code := m.Run()
+	scout.Stop()
+	os.Exit(code)
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span, _ := scout.StartTrace(r.Context(), "handler")
+		scout.EndTrace(span)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/charges/42", nil)
+	req.Header.Set(scout.RequestTracerHeader, "session/cmVxdWVzdA==")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Contains(t, spans[0].Attributes(), attribute.String(scout.SessionIDAttribute, "session"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String(scout.RequestIDAttribute, "cmVxdWVzdA=="))
+	assert.Contains(t, spans[1].Attributes(), attribute.String(scout.SourceAttribute, "GoGorillaMuxMiddleware"))
+	assert.Contains(t, spans[1].Attributes(), semconv.HTTPStatusCode(http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
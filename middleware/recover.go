@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RecoverHandler catches the panics escaping next, the root handler of a server, so panics are recorded
+// even on routes missing a Scout middleware. Panics are recorded with the session and request of the
+// X-Scout-Request header when the request has one, and on their own otherwise. The panic then resumes,
+// so net/http still logs it and aborts the response.
+func RecoverHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler aborts a response on purpose and is not reported by net/http either
+			if recovered != http.ErrAbortHandler && scout.IsRunning() {
+				ctx := scout.ContextWithRequestAttributes(scout.InterceptRequest(r))
+				tags := append(GetRequestAttributes(r), attribute.String(scout.SourceAttribute, "RecoverHandler"))
+				scout.RecordPanic(ctx, recovered, tags...)
+			}
+			panic(recovered)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RecoverServer wraps the handler of srv with RecoverHandler, using http.DefaultServeMux when srv has
+// no handler, as net/http does:
+//
+//	srv := &http.Server{Addr: ":8080", Handler: router}
+//	middleware.RecoverServer(srv)
+//	srv.ListenAndServe()
+func RecoverServer(srv *http.Server) *http.Server {
+	handler := srv.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	srv.Handler = RecoverHandler(handler)
+	return srv
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	scout.Start(scout.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), scout.WithoutOTLPExporter())
+	code := m.Run()
+	scout.Stop()
+	os.Exit(code)
+}
+
+// exceptions returns the exception events recorded on the spans after the first ended ones.
+func exceptions(spans []sdktrace.ReadOnlySpan, ended int) []sdktrace.Event {
+	var events []sdktrace.Event
+	for _, span := range spans[ended:] {
+		for _, event := range span.Events() {
+			if event.Name == semconv.ExceptionEventName {
+				events = append(events, event)
+			}
+		}
+	}
+	return events
+}
+
+func TestRecoverHandler(t *testing.T) {
+	failed := errors.New("charge failed")
+	handler := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(failed)
+	}))
+	ended := len(recorder.Ended())
+	assert.PanicsWithValue(t, failed, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/charge", nil))
+	}, "the panic resumes once recorded")
+	events := exceptions(recorder.Ended(), ended)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0].Attributes, semconv.ExceptionMessage("charge failed"))
+
+	aborted := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	ended = len(recorder.Ended())
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		aborted.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream", nil))
+	})
+	assert.Empty(t, exceptions(recorder.Ended(), ended), "aborted responses are not recorded")
+}
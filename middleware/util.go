@@ -1,6 +1,11 @@
 package middleware
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"strings"
 
@@ -8,7 +13,9 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func AssertScoutIsRunning() {
@@ -17,6 +24,102 @@ func AssertScoutIsRunning() {
 	}
 }
 
+// SetRequestIDHeader echoes the request ID generated for the request in the response headers, if any.
+func SetRequestIDHeader(ctx context.Context, header http.Header) {
+	if id := scout.GeneratedRequestID(ctx); id != "" {
+		header.Set(scout.RequestIDResponseHeader, id)
+	}
+}
+
+// SetSpanStatus marks the span of a request to route as an error if scout.IsErrorStatus classifies
+// the response status as one.
+func SetSpanStatus(span trace.Span, route string, status int) {
+	if scout.IsErrorStatus(route, status) {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
+
+// StatusRecorder is a http.ResponseWriter recording the status of the response, for net/http middleware.
+type StatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// NewStatusRecorder returns a StatusRecorder writing the response to w.
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w}
+}
+
+func (w *StatusRecorder) WriteHeader(status int) {
+	// informational responses precede the final status
+	if w.status == 0 && status >= http.StatusOK {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *StatusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the response if the underlying writer supports it.
+func (w *StatusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection if the underlying writer supports it, for handlers upgrading it to
+// a WebSocket connection for example. The status of a hijacked response is http.StatusSwitchingProtocols
+// unless the handler wrote one.
+func (w *StatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not support hijacking: %w", w.ResponseWriter, http.ErrNotSupported)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// ReadFrom copies the response body from r, using the underlying writer's ReadFrom if any so files are
+// still sent with sendfile.
+func (w *StatusRecorder) ReadFrom(r io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(r)
+	}
+	return io.Copy(w.ResponseWriter, r)
+}
+
+// Push initiates an HTTP/2 server push if the underlying writer supports it.
+func (w *StatusRecorder) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *StatusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status of the response, which is http.StatusOK if the handler wrote none.
+func (w *StatusRecorder) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
 func GetIPAddress(r *http.Request) string {
 	IPAddress := r.Header.Get("X-Real-Ip")
 	if IPAddress == "" {
@@ -42,11 +145,13 @@ func GetRequestAttributes(r *http.Request) []attribute.KeyValue {
 		attribute.String(string(semconv.HTTPClientIPKey), GetIPAddress(r)),
 	}
 	if r.URL != nil {
+		u := scout.RedactURL(r.URL)
 		attrs = append(attrs,
-			attribute.String(string(semconv.HTTPURLKey), r.URL.String()),
-			attribute.String(string(semconv.HTTPRouteKey), r.URL.RequestURI()),
+			attribute.String(string(semconv.HTTPURLKey), u.String()),
+			attribute.String(string(semconv.HTTPRouteKey), u.RequestURI()),
 		)
 	}
+	attrs = append(attrs, scout.RequestHeaderAttributes(r.Header)...)
 	if r.Response != nil {
 		attrs = append(attrs, attribute.Int(string(semconv.HTTPStatusCodeKey), r.Response.StatusCode))
 	}
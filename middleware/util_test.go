@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusRecorderHijack(t *testing.T) {
+	statuses := make(chan int, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := NewStatusRecorder(w)
+		// WebSocket libraries assert the writer is a http.Hijacker rather than unwrapping it
+		hijacker, ok := http.ResponseWriter(rec).(http.Hijacker)
+		require.True(t, ok)
+		conn, rw, err := hijacker.Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		_ = rw.Flush()
+		statuses <- rec.Status()
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Equal(t, http.StatusSwitchingProtocols, <-statuses)
+
+	_, _, err = NewStatusRecorder(httptest.NewRecorder()).Hijack()
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+}
+
+func TestStatusRecorderReadFrom(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := NewStatusRecorder(w)
+	n, err := rec.ReadFrom(strings.NewReader("body"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+	assert.Equal(t, "body", w.Body.String())
+	assert.Equal(t, http.StatusOK, rec.Status())
+	assert.ErrorIs(t, rec.Push("/style.css", nil), http.ErrNotSupported)
+}
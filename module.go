@@ -0,0 +1,47 @@
+package scout
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ModuleTracer starts spans scoped to a module of the application.
+// Span names are prefixed with the module name and spans carry the scout.module attribute.
+type ModuleTracer struct {
+	module string
+}
+
+// Tracer returns a tracer for the named module, eg: scout.Tracer("billing").
+func Tracer(module string) ModuleTracer {
+	return ModuleTracer{module: module}
+}
+
+func (t ModuleTracer) spanName(name string) string {
+	return t.module + "." + name
+}
+
+// StartTraceWithOptions is StartTraceWithOptions scoped to the module.
+func (t ModuleTracer) StartTraceWithOptions(ctx context.Context, name string, opts ...TraceOption) (trace.Span, context.Context) {
+	opts = append([]TraceOption{WithTags(attribute.String(ModuleAttribute, t.module))}, opts...)
+	return StartTraceWithOptions(ctx, t.spanName(name), opts...)
+}
+
+// StartTrace is StartTrace scoped to the module.
+func (t ModuleTracer) StartTrace(ctx context.Context, name string, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+	return t.StartTraceWithOptions(ctx, name, WithTags(tags...))
+}
+
+// StartSpan is StartSpan scoped to the module.
+func (t ModuleTracer) StartSpan(ctx context.Context, name string, tags ...attribute.KeyValue) (trace.Span, context.Context, func(err *error)) {
+	span, ctx := t.StartTrace(ctx, name, tags...)
+	return span, ctx, finisher(span)
+}
+
+// Trace is Trace scoped to the module.
+func (t ModuleTracer) Trace(ctx context.Context, name string, fn func(ctx context.Context) error, tags ...attribute.KeyValue) (err error) {
+	_, ctx, finish := t.StartSpan(ctx, name, tags...)
+	defer finish(&err)
+	return fn(ctx)
+}
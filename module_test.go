@@ -0,0 +1,35 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestModuleTracer(t *testing.T) {
+	recorder := recordSpans(t)
+	billing := Tracer("billing")
+
+	span, ctx := billing.StartTrace(context.Background(), "invoice", attribute.String("invoice.id", "42"))
+	err := billing.Trace(ctx, "charge", func(ctx context.Context) error {
+		return errors.New("declined")
+	})
+	EndTrace(span)
+	require.EqualError(t, err, "declined")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	charge, invoice := spans[0], spans[1]
+	assert.Equal(t, "billing.invoice", invoice.Name())
+	assert.Contains(t, invoice.Attributes(), attribute.String(ModuleAttribute, "billing"))
+	assert.Contains(t, invoice.Attributes(), attribute.String("invoice.id", "42"))
+	assert.Equal(t, "billing.charge", charge.Name())
+	assert.Contains(t, charge.Attributes(), attribute.String(ModuleAttribute, "billing"))
+	assert.Equal(t, invoice.SpanContext().SpanID(), charge.Parent().SpanID())
+	assert.Equal(t, codes.Error, charge.Status().Code)
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/smithy-go/ptr"
@@ -16,8 +17,10 @@ import (
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	ocbridge "go.opentelemetry.io/otel/bridge/opencensus"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/zipkin"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
@@ -28,12 +31,24 @@ const OTLPDefaultEndpoint = "https://otel.getscout.us:4318"
 
 const ErrorURLAttribute = "URL"
 
+// ErrorCountAttribute holds the number of occurrences of an error recorded by RecordErrors.
+const ErrorCountAttribute = "exception.count"
+
 const ProjectIDAttribute = "scout.project_id"
 const SessionIDAttribute = "scout.session_id"
 const RequestIDAttribute = "scout.trace_id"
 const SourceAttribute = "scout.source"
 const TraceTypeAttribute = "scout.type"
 const TraceKeyAttribute = "scout.key"
+const ModuleAttribute = "scout.module"
+
+// ForceSampleAttribute is a span start attribute that makes the span sampled regardless of the
+// configured sampling rates, such as for logs of a sampled trace.
+const ForceSampleAttribute = "scout.sample.force"
+
+// forceSampleTraceStateKey is the tracestate entry set by ForceSample, which propagates with the trace context.
+const forceSampleTraceStateKey = "scout"
+const forceSampleTraceStateValue = "force"
 
 const LogEvent = "log"
 const LogSeverityAttribute = "log.severity"
@@ -42,6 +57,10 @@ const LogMessageAttribute = "log.message"
 const MetricEvent = "metric"
 const MetricEventName = "metric.name"
 const MetricEventValue = "metric.value"
+const MetricEventType = "metric.type"
+
+const BusinessEvent = "event"
+const BusinessEventName = "event.name"
 
 type TraceType string
 
@@ -50,6 +69,17 @@ const TraceTypeScoutInternal TraceType = "scout.internal"
 
 type OTLP struct {
 	tracerProvider *sdktrace.TracerProvider
+	// ownsProvider is false when scout attached its processors to a provider supplied by the application
+	ownsProvider bool
+	processors   []sdktrace.SpanProcessor
+	// executionTracer is set when slow request tracing is enabled
+	executionTracer *executionTracer
+	// apdex is set when Apdex scores are computed
+	apdex *apdexProcessor
+	// alerts is set when watchers are registered
+	alerts *alertProcessor
+	// logs is set when logs are exported with the OTLP logs signal
+	logs *logExporter
 }
 
 type ErrorWithStack interface {
@@ -60,10 +90,25 @@ type ErrorWithStack interface {
 type sampler struct {
 	traceIDUpperBounds map[trace.SpanKind]uint64
 	description        string
+	// recordUnsampled records spans that are sampled out so they can still be exported if they turn out slow
+	recordUnsampled bool
 }
 
 func (s sampler) ShouldSample(sp sdktrace.SamplingParameters) sdktrace.SamplingResult {
 	psc := trace.SpanContextFromContext(sp.ParentContext)
+	// internal telemetry such as heartbeats and deploys is always kept, as are force sampled spans and traces
+	if psc.TraceState().Get(forceSampleTraceStateKey) == forceSampleTraceStateValue {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: psc.TraceState(),
+		}
+	}
+	if alwaysSampled(sp.Attributes) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: psc.TraceState(),
+		}
+	}
 	x := binary.BigEndian.Uint64(sp.TraceID[8:16]) >> 1
 	bound, ok := s.traceIDUpperBounds[sp.Kind]
 	if !ok {
@@ -75,50 +120,237 @@ func (s sampler) ShouldSample(sp sdktrace.SamplingParameters) sdktrace.SamplingR
 			Tracestate: psc.TraceState(),
 		}
 	}
+	decision := sdktrace.Drop
+	if s.recordUnsampled {
+		decision = sdktrace.RecordOnly
+	}
 	return sdktrace.SamplingResult{
-		Decision:   sdktrace.Drop,
+		Decision:   decision,
 		Tracestate: psc.TraceState(),
 	}
 }
 
+// weight returns the number of spans s stands for given the sampling rate of its kind, which is zero for
+// the spans that were recorded without being sampled, as the sampled spans stand for them.
+func (s sampler) weight(span sdktrace.ReadOnlySpan) float64 {
+	sc := span.SpanContext()
+	if !sc.IsSampled() {
+		return 0
+	}
+	if sc.TraceState().Get(forceSampleTraceStateKey) == forceSampleTraceStateValue {
+		return 1
+	}
+	if alwaysSampled(span.Attributes()) {
+		return 1
+	}
+	bound, ok := s.traceIDUpperBounds[span.SpanKind()]
+	if !ok {
+		bound = s.traceIDUpperBounds[trace.SpanKindUnspecified]
+	}
+	if rate := float64(bound) / (1 << 63); rate > 0 && rate < 1 {
+		return 1 / rate
+	}
+	return 1
+}
+
+// alwaysSampled reports whether a span with attrs is internal telemetry or force sampled, which bypass
+// the sampling rates.
+func alwaysSampled(attrs []attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv.Key == TraceTypeAttribute && kv.Value.AsString() == string(TraceTypeScoutInternal) ||
+			kv.Key == ForceSampleAttribute && kv.Value.AsBool() {
+			return true
+		}
+	}
+	return false
+}
+
 func (s sampler) Description() string {
 	return s.description
 }
 
 // creates a per-span-kind sampler that samples each kind at a provided fraction.
 func getSampler() sampler {
+	conf := loadConfig()
 	return sampler{
-		description: fmt.Sprintf("TraceIDRatioBased{%+v}", conf.samplingRateMap),
+		description:     fmt.Sprintf("TraceIDRatioBased{%+v}", conf.samplingRateMap),
+		recordUnsampled: conf.slowSpanSampling && len(conf.slowSpanThresholds) > 0,
 		traceIDUpperBounds: lo.MapEntries(conf.samplingRateMap, func(key trace.SpanKind, value float64) (trace.SpanKind, uint64) {
 			return key, uint64(value * (1 << 63))
 		}),
 	}
 }
 
-var (
-	tracer = otel.GetTracerProvider().Tracer(
+// ForceSample returns a copy of ctx whose trace bypasses the configured sampling rates, for code paths
+// such as a failed payment or an admin action that should always be captured. Spans started from the
+// returned context are sampled, including in services the trace propagates to through the tracestate
+// header. The span already active in ctx keeps its own sampling decision.
+func ForceSample(ctx context.Context) context.Context {
+	span := trace.SpanFromContext(ctx)
+	sc := span.SpanContext()
+	ts, err := sc.TraceState().Insert(forceSampleTraceStateKey, forceSampleTraceStateValue)
+	if err != nil {
+		logger.Error(err)
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, forceSampledSpan{Span: span, spanContext: sc.WithTraceState(ts)})
+}
+
+// forceSampledSpan is the active span with the tracestate set by ForceSample, so children inherit it.
+type forceSampledSpan struct {
+	trace.Span
+	spanContext trace.SpanContext
+}
+
+func (s forceSampledSpan) SpanContext() trace.SpanContext {
+	return s.spanContext
+}
+
+// activeTracer holds the tracer spans are started with, swapped when Scout starts while spans are started
+// from other goroutines.
+var activeTracer atomic.Pointer[trace.Tracer]
+
+func init() {
+	storeTracer(newTracer(otel.GetTracerProvider()))
+}
+
+func loadTracer() trace.Tracer {
+	return *activeTracer.Load()
+}
+
+func storeTracer(t trace.Tracer) {
+	activeTracer.Store(&t)
+}
+
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	return tp.Tracer(
 		"github.com/scout-inc/scout-go",
-		trace.WithInstrumentationVersion("v0.1.0"),
+		trace.WithInstrumentationVersion(instrumentationVersion()),
 		trace.WithSchemaURL(semconv.SchemaURL),
 	)
-)
+}
 
-func StartOTLP() (*OTLP, error) {
+func newTraceClient(endpoint string) (otlptrace.Client, error) {
+	conf := loadConfig()
 	var options []otlptracehttp.Option
-	if strings.HasPrefix(conf.otelEndpoint, "http://") {
-		options = append(options, otlptracehttp.WithEndpoint(conf.otelEndpoint[7:]), otlptracehttp.WithInsecure())
-	} else if strings.HasPrefix(conf.otelEndpoint, "https://") {
-		options = append(options, otlptracehttp.WithEndpoint(conf.otelEndpoint[8:]))
+	if strings.HasPrefix(endpoint, "http://") {
+		options = append(options, otlptracehttp.WithEndpoint(endpoint[7:]), otlptracehttp.WithInsecure())
+	} else if strings.HasPrefix(endpoint, "https://") {
+		options = append(options, otlptracehttp.WithEndpoint(endpoint[8:]))
 	} else {
-		logger.Errorf("an invalid otlp endpoint was configured %s", conf.otelEndpoint)
+		logger.Errorf("an invalid otlp endpoint was configured %s", endpoint)
+	}
+	switch conf.compression {
+	case CompressionZstd:
+		return newZstdClient(endpoint)
+	case CompressionNone:
+		options = append(options, otlptracehttp.WithCompression(otlptracehttp.NoCompression))
+	default:
+		options = append(options, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+	if conf.exportTimeout > 0 {
+		options = append(options, otlptracehttp.WithTimeout(conf.exportTimeout))
+	}
+	return otlptracehttp.NewClient(options...), nil
+}
+
+func StartOTLP() (*OTLP, error) {
+	conf := loadConfig()
+	var budget *memoryBudget
+	if conf.memoryLimit > 0 {
+		budget = newMemoryBudget(conf.memoryLimit, conf.dropPolicy, conf.signalPriority)
 	}
-	options = append(options, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
-	client := otlptracehttp.NewClient(options...)
-	exporter, err := otlptrace.New(context.Background(), client)
+	exportProcessors, err := newExportProcessors(budget)
 	if err != nil {
-		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+		return nil, err
+	}
+	// application processors run before the exporters so they can enrich spans before export
+	processors := append(append([]sdktrace.SpanProcessor{}, conf.spanProcessors...), exportProcessors...)
+	// enrichment runs first so every other processor sees the attributes it sets
+	if len(conf.enrichmentRules) > 0 {
+		processors = append([]sdktrace.SpanProcessor{enrichmentProcessor{rules: conf.enrichmentRules}}, processors...)
+	}
+	h := &OTLP{}
+	if conf.slowRequestThreshold > 0 {
+		h.executionTracer = newExecutionTracer(conf.slowRequestThreshold)
+		processors = append([]sdktrace.SpanProcessor{h.executionTracer}, processors...)
+	}
+	if conf.apdexThreshold > 0 && conf.apdexPeriod > 0 {
+		h.apdex = newApdexProcessor(conf.apdexThreshold)
+		processors = append(processors, h.apdex)
+	}
+	processors = append(processors, debugSpans)
+	if len(conf.alertWatchers) > 0 {
+		interval := conf.alertInterval
+		if interval <= 0 {
+			interval = DefaultAlertInterval
+		}
+		var s *sampler
+		if conf.tracerProvider == nil {
+			sampler := getSampler()
+			s = &sampler
+		}
+		h.alerts = newAlertProcessor(conf.alertWatchers, interval, s)
+		processors = append(processors, h.alerts)
+	}
+	h.processors = processors
+	var otelResource *resource.Resource
+	if conf.tracerProvider != nil {
+		h.tracerProvider = conf.tracerProvider
+		for _, processor := range processors {
+			h.tracerProvider.RegisterSpanProcessor(processor)
+		}
+		if !conf.privateTracerProvider {
+			registerPropagator()
+		}
+	} else {
+		otelResource, err = newResource()
+		if err != nil {
+			shutdownProcessors(processors)
+			return nil, fmt.Errorf("creating OTLP resource context: %w", err)
+		}
+		providerOptions := []sdktrace.TracerProviderOption{
+			sdktrace.WithSampler(getSampler()),
+			sdktrace.WithResource(otelResource),
+		}
+		if conf.spanLimits != nil {
+			providerOptions = append(providerOptions, sdktrace.WithRawSpanLimits(*conf.spanLimits))
+		}
+		if conf.idGenerator != nil {
+			providerOptions = append(providerOptions, sdktrace.WithIDGenerator(conf.idGenerator))
+		}
+		for _, processor := range processors {
+			providerOptions = append(providerOptions, sdktrace.WithSpanProcessor(processor))
+		}
+		h.tracerProvider = sdktrace.NewTracerProvider(providerOptions...)
+		h.ownsProvider = true
+		if !conf.privateTracerProvider {
+			otel.SetTracerProvider(h.tracerProvider)
+			registerPropagator()
+		}
+	}
+	storeTracer(newTracer(h.tracerProvider))
+	if !conf.disableOTLP && !conf.logSpanEvents {
+		if otelResource == nil {
+			otelResource, err = newResource()
+		}
+		if err != nil {
+			logger.Errorf("creating OTLP resource context for logs, exporting logs as span events: %s", err)
+		} else {
+			h.logs = newLogExporter(otlpEndpoint(), otelResource, budget)
+		}
+	}
+	if conf.openCensusBridge {
+		ocbridge.InstallTraceBridge(ocbridge.WithTracerProvider(h.tracerProvider))
 	}
-	otelResource, err := resource.New(context.Background(),
+	return h, nil
+}
+
+// newResource describes the service and the host it runs on, for the exported telemetry.
+func newResource() (*resource.Resource, error) {
+	conf := loadConfig()
+	return resource.New(context.Background(),
+		resource.WithAttributes(buildResourceAttributes()...),
 		resource.WithFromEnv(),
 		resource.WithHost(),
 		resource.WithContainer(),
@@ -126,82 +358,267 @@ func StartOTLP() (*OTLP, error) {
 		resource.WithProcess(),
 		resource.WithAttributes(conf.resourceAttributes...),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("creating OTLP resource context: %w", err)
+}
+
+// exportTarget is an exporter and the stats of its exports.
+type exportTarget struct {
+	exporter sdktrace.SpanExporter
+	stats    *exporterStats
+}
+
+// newExportProcessors creates a processor for each configured exporter or, with the budget of a memory limit,
+// a single processor sharing the budget between the exporters.
+func newExportProcessors(budget *memoryBudget) ([]sdktrace.SpanProcessor, error) {
+	conf := loadConfig()
+	resetExporterStats()
+	var targets []exportTarget
+	type exportEndpoint struct {
+		stats *exporterStats
+		// failover lists the endpoints to fail over to, in order of priority
+		failover []string
 	}
-	h := &OTLP{
-		tracerProvider: sdktrace.NewTracerProvider(
-			sdktrace.WithSampler(getSampler()),
-			sdktrace.WithBatcher(
-				exporter,
-				sdktrace.WithBatchTimeout(1000*time.Millisecond),
-				sdktrace.WithMaxExportBatchSize(128),
-				sdktrace.WithMaxQueueSize(1024)),
-			sdktrace.WithResource(otelResource),
-		),
+	var endpoints []exportEndpoint
+	if !conf.disableOTLP {
+		endpoints = append(endpoints, exportEndpoint{
+			stats:    &exporterStats{name: "otlp", endpoint: otlpEndpoint()},
+			failover: conf.failoverEndpoints,
+		})
 	}
-	otel.SetTracerProvider(h.tracerProvider)
-	return h, nil
+	if conf.datadogAgentEndpoint != "" {
+		endpoints = append(endpoints, exportEndpoint{stats: &exporterStats{name: "datadog", endpoint: conf.datadogAgentEndpoint}})
+	}
+	for _, endpoint := range endpoints {
+		stats := endpoint.stats
+		logger.Debugf("exporting spans to %s endpoint %s", stats.name, redactEndpoint(stats.endpoint))
+		var client otlptrace.Client
+		var err error
+		if len(endpoint.failover) > 0 {
+			client, err = newFailoverClient(append([]string{stats.endpoint}, endpoint.failover...))
+		} else {
+			client, err = newTraceClient(stats.endpoint)
+		}
+		if err != nil {
+			shutdownTargets(targets)
+			return nil, fmt.Errorf("creating OTLP trace client: %w", err)
+		}
+		exporter, err := otlptrace.New(context.Background(), client)
+		if err != nil {
+			shutdownTargets(targets)
+			return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+		}
+		targets = append(targets, exportTarget{exporter: exporter, stats: stats})
+	}
+	if conf.zipkinEndpoint != "" {
+		exporter, err := zipkin.New(conf.zipkinEndpoint)
+		if err != nil {
+			shutdownTargets(targets)
+			return nil, fmt.Errorf("creating zipkin trace exporter: %w", err)
+		}
+		targets = append(targets, exportTarget{exporter: exporter, stats: &exporterStats{name: "zipkin", endpoint: conf.zipkinEndpoint}})
+	}
+	if conf.consoleExporter {
+		targets = append(targets, exportTarget{exporter: newConsoleExporter(), stats: &exporterStats{name: "console", endpoint: "stderr"}})
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	if budget != nil {
+		return []sdktrace.SpanProcessor{newBudgetExportProcessor(targets, budget)}, nil
+	}
+	processors := make([]sdktrace.SpanProcessor, 0, len(targets))
+	for _, target := range targets {
+		processors = append(processors, newExportProcessor(target.exporter, target.stats))
+	}
+	return processors, nil
+}
+
+func shutdownTargets(targets []exportTarget) {
+	for _, target := range targets {
+		if err := target.exporter.Shutdown(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+func shutdownProcessors(processors []sdktrace.SpanProcessor) {
+	for _, processor := range processors {
+		if err := processor.Shutdown(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// exportBatching returns the batch timeout and the maximum batch size of the span export processors, set
+// with WithExportBatching.
+func exportBatching(conf *config) (time.Duration, int) {
+	batchTimeout, batchSize := 1000*time.Millisecond, 128
+	if conf.exportBatchTimeout > 0 {
+		batchTimeout = conf.exportBatchTimeout
+	}
+	if conf.exportBatchSize > 0 {
+		batchSize = conf.exportBatchSize
+	}
+	return batchTimeout, batchSize
+}
+
+// newExportProcessor batches spans for the exporter. Spans pass through the configured filters before
+// being batched.
+func newExportProcessor(exporter sdktrace.SpanExporter, stats *exporterStats) sdktrace.SpanProcessor {
+	conf := loadConfig()
+	registerExporterStats(stats)
+	exporter = instrumentedExporter{SpanExporter: exporter, stats: stats, timeout: conf.exportTimeout}
+	batchTimeout, batchSize := exportBatching(conf)
+	options := []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithBatchTimeout(batchTimeout),
+		sdktrace.WithMaxExportBatchSize(batchSize),
+		sdktrace.WithMaxQueueSize(max(1024, 8*batchSize)),
+	}
+	if conf.exportTimeout > 0 {
+		options = append(options, sdktrace.WithExportTimeout(conf.exportTimeout))
+	}
+	processor := sdktrace.NewBatchSpanProcessor(exporter, options...)
+	return filterProcessor{next: processor, filters: spanFilters(), stats: []*exporterStats{stats}}
+}
+
+// newBudgetExportProcessor batches spans for all the exporters in a single queue bounded by the budget of the
+// configured memory limit, so the limit caps the memory buffered for export however many exporters are
+// configured. Spans pass through the configured filters before being batched.
+func newBudgetExportProcessor(targets []exportTarget, budget *memoryBudget) sdktrace.SpanProcessor {
+	conf := loadConfig()
+	exporters := make(multiExporter, 0, len(targets))
+	stats := make([]*exporterStats, 0, len(targets))
+	for _, target := range targets {
+		registerExporterStats(target.stats)
+		exporters = append(exporters, instrumentedExporter{SpanExporter: target.exporter, stats: target.stats, timeout: conf.exportTimeout})
+		stats = append(stats, target.stats)
+	}
+	batchTimeout, batchSize := exportBatching(conf)
+	processor := newBudgetProcessor(exporters, budget, batchTimeout, batchSize)
+	return filterProcessor{next: processor, filters: spanFilters(), stats: stats}
+}
+
+// flush exports the spans and logs buffered by scout's processors, returning the last error.
+func (o *OTLP) flush(ctx context.Context) error {
+	var lastErr error
+	if o.logs != nil {
+		lastErr = o.logs.flush(ctx)
+	}
+	if o.ownsProvider {
+		if err := o.tracerProvider.ForceFlush(ctx); err != nil {
+			return err
+		}
+		return lastErr
+	}
+	for _, processor := range o.processors {
+		if err := processor.ForceFlush(ctx); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
 }
 
-func (o *OTLP) shutdown() {
-	err := o.tracerProvider.ForceFlush(context.Background())
+func (o *OTLP) shutdown(ctx context.Context) {
+	if o.logs != nil {
+		if err := o.logs.flush(ctx); err != nil {
+			logger.Error(err)
+		}
+		o.logs.shutdown()
+	}
+	if !o.ownsProvider {
+		// only detach scout's processors, the application manages its own provider
+		for _, processor := range o.processors {
+			if err := processor.ForceFlush(ctx); err != nil {
+				logger.Error(err)
+			}
+			o.tracerProvider.UnregisterSpanProcessor(processor)
+		}
+		return
+	}
+	err := o.tracerProvider.ForceFlush(ctx)
 	if err != nil {
 		logger.Error(err)
 	}
-	err = o.tracerProvider.Shutdown(context.Background())
+	err = o.tracerProvider.Shutdown(ctx)
 	if err != nil {
 		logger.Error(err)
 	}
 }
 
-func StartTraceWithTimestamp(ctx context.Context, name string, t time.Time, opts []trace.SpanStartOption, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+// StartTraceWithOptions starts a span configured by options such as WithTimestamp, WithSpanKindOption,
+// WithoutResourceAttrs and WithTags. StartTrace, StartTraceWithTimestamp and StartTraceWithoutResourceAttributes
+// are shorthands for it.
+func StartTraceWithOptions(ctx context.Context, name string, opts ...TraceOption) (trace.Span, context.Context) {
+	cfg := newTraceConfig(opts)
 	sessionID, requestID, _ := validateRequest(ctx)
 	spanCtx := trace.SpanContextFromContext(ctx)
 
 	if requestID != "" {
-		data, _ := base64.StdEncoding.DecodeString(requestID)
-		hex := fmt.Sprintf("%032x", data)
-		tid, _ := trace.TraceIDFromHex(hex)
-		spanCtx = spanCtx.WithTraceID(tid)
+		spanCtx = spanCtx.WithTraceID(requestTraceID(requestID))
 	}
 
-	opts = append(opts, trace.WithTimestamp(t))
-	ctx, span := tracer.Start(trace.ContextWithSpanContext(ctx, spanCtx), name, opts...)
+	startOptions := append(cfg.startOptions, trace.WithTimestamp(cfg.timestamp))
+	ctx, span := loadTracer().Start(trace.ContextWithSpanContext(ctx, spanCtx), name, startOptions...)
+	// the sampler dropped the span, so skip building attributes that would be discarded
+	if !span.IsRecording() {
+		return span, ctx
+	}
 	span.SetAttributes(
-		attribute.String(ProjectIDAttribute, conf.projectID),
+		attribute.String(ProjectIDAttribute, ProjectIDFromContext(ctx)),
 		attribute.String(SessionIDAttribute, sessionID),
 		attribute.String(RequestIDAttribute, requestID),
 	)
+	if cfg.withoutResourceAttributes {
+		span.SetAttributes(emptyResourceAttributes...)
+	}
 	// prioritize values passed in tags for project, session, request IDs
-	span.SetAttributes(tags...)
+	span.SetAttributes(cfg.tags...)
 	return span, ctx
 }
 
+// requestTraceID returns the ID of the trace of the spans of a Scout request.
+func requestTraceID(requestID string) trace.TraceID {
+	data, _ := base64.StdEncoding.DecodeString(requestID)
+	tid, _ := trace.TraceIDFromHex(fmt.Sprintf("%032x", data))
+	return tid
+}
+
+func StartTraceWithTimestamp(ctx context.Context, name string, t time.Time, opts []trace.SpanStartOption, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+	return StartTraceWithOptions(ctx, name, WithTimestamp(t), WithSpanStartOptions(opts...), WithTags(tags...))
+}
+
 func StartTrace(ctx context.Context, name string, tags ...attribute.KeyValue) (trace.Span, context.Context) {
-	return StartTraceWithTimestamp(ctx, name, time.Now(), nil, tags...)
+	return StartTraceWithOptions(ctx, name, WithTags(tags...))
 }
 
-func StartTraceWithoutResourceAttributes(ctx context.Context, name string, opts []trace.SpanStartOption, tags ...attribute.KeyValue) (trace.Span, context.Context) {
-	resourceAttributes := []attribute.KeyValue{
-		semconv.ServiceNameKey.String(""),
-		semconv.ServiceVersionKey.String(""),
-		semconv.ContainerIDKey.String(""),
-		semconv.HostNameKey.String(""),
-		semconv.OSDescriptionKey.String(""),
-		semconv.OSTypeKey.String(""),
-		semconv.ProcessExecutableNameKey.String(""),
-		semconv.ProcessExecutablePathKey.String(""),
-		semconv.ProcessOwnerKey.String(""),
-		semconv.ProcessPIDKey.String(""),
-		semconv.ProcessRuntimeDescriptionKey.String(""),
-		semconv.ProcessRuntimeNameKey.String(""),
-		semconv.ProcessRuntimeVersionKey.String(""),
-	}
+// emptyResourceAttributes blank out the resource attributes of spans submitted on behalf of other services.
+var emptyResourceAttributes = []attribute.KeyValue{
+	semconv.ServiceNameKey.String(""),
+	semconv.ServiceVersionKey.String(""),
+	semconv.ContainerIDKey.String(""),
+	semconv.HostNameKey.String(""),
+	semconv.OSDescriptionKey.String(""),
+	semconv.OSTypeKey.String(""),
+	semconv.ProcessExecutableNameKey.String(""),
+	semconv.ProcessExecutablePathKey.String(""),
+	semconv.ProcessOwnerKey.String(""),
+	semconv.ProcessPIDKey.String(""),
+	semconv.ProcessRuntimeDescriptionKey.String(""),
+	semconv.ProcessRuntimeNameKey.String(""),
+	semconv.ProcessRuntimeVersionKey.String(""),
+}
 
-	attrs := append(resourceAttributes, tags...)
+// startInternalTrace starts a span for telemetry about the SDK or the process rather than a request.
+// Internal spans are exempt from sampling.
+func startInternalTrace(ctx context.Context, name string, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+	internal := attribute.String(TraceTypeAttribute, string(TraceTypeScoutInternal))
+	return StartTraceWithOptions(ctx, name,
+		WithSpanStartOptions(trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(internal)),
+		WithTags(tags...),
+	)
+}
 
-	return StartTraceWithTimestamp(ctx, name, time.Now(), opts, attrs...)
+func StartTraceWithoutResourceAttributes(ctx context.Context, name string, opts []trace.SpanStartOption, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+	return StartTraceWithOptions(ctx, name, WithSpanStartOptions(opts...), WithoutResourceAttrs(), WithTags(tags...))
 }
 
 func EndTrace(span trace.Span) {
@@ -213,10 +630,51 @@ func EndTrace(span trace.Span) {
 // Scout will process these metrics in the context of your session and expose them through charts.
 //
 // For example, you may want to record the latency of a database query as a metric that you can graph and monitor.
+//
+// Each metric is exported on its own span, unless WithMetricBatching is set.
+//
+// Metric names and tag keys start with a letter, are made of letters, digits, '_', '.', '-' and '/',
+// and are at most MaxMetricNameLength and MaxMetricTagKeyLength characters long. Metrics with an invalid
+// name and tags with an invalid key are dropped, and logged in debug mode.
 func RecordMetric(ctx context.Context, name string, value float64, tags ...attribute.KeyValue) {
-	span, _ := StartTraceWithTimestamp(ctx, ScopedKey("metric", ptr.String("-")), time.Now(), []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}, tags...)
+	RecordMetricWithTimestamp(ctx, name, value, time.Now(), tags...)
+}
+
+// RecordMetricWithTimestamp is RecordMetric for a metric measured at t rather than now,
+// such as a metric replayed from a buffer or a batch import.
+func RecordMetricWithTimestamp(ctx context.Context, name string, value float64, t time.Time, tags ...attribute.KeyValue) {
+	conf := loadConfig()
+	name, tags, ok := normalizeMetric(name, tags)
+	if !ok {
+		return
+	}
+	if conf.metricBatchInterval > 0 && IsRunning() {
+		addMetric(ctx, name, value, t, tags)
+		return
+	}
+	span, _ := StartTraceWithTimestamp(ctx, ScopedKey("metric", ptr.String("-")), t, []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}, tags...)
+	defer span.End(trace.WithTimestamp(t), trace.WithStackTrace(true))
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(MetricEvent, trace.WithAttributes(attribute.String(MetricEventName, name), attribute.Float64(MetricEventValue, value)), trace.WithTimestamp(t))
+}
+
+// RecordEvent records a business event that is neither an error nor a metric, such as a user being invited
+// or an export being generated, attributed to the session and request in ctx. The event is recorded with
+// attrs on a span of its own, and is never dropped by sampling so it can be relied on for auditing:
+//
+//	scout.RecordEvent(ctx, "user.invited", attribute.String("user.role", "admin"))
+func RecordEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	span, _ := StartTraceWithOptions(ctx, ScopedKey("event", ptr.String("-")),
+		WithSpanStartOptions(trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.Bool(ForceSampleAttribute, true))),
+	)
 	defer EndTrace(span)
-	span.AddEvent(MetricEvent, trace.WithAttributes(attribute.String(MetricEventName, name), attribute.Float64(MetricEventValue, value)))
+	if !span.IsRecording() {
+		return
+	}
+	attrs = append([]attribute.KeyValue{attribute.String(BusinessEventName, name)}, attrs...)
+	span.AddEvent(BusinessEvent, trace.WithAttributes(attrs...))
 }
 
 // RecordError processes `err` to be recorded as a part of the session or network request.
@@ -231,26 +689,105 @@ func RecordError(ctx context.Context, err error, tags ...attribute.KeyValue) con
 	return ctx
 }
 
+// RecordErrorWithTimestamp is RecordError for an error that occurred at t rather than now,
+// such as an error replayed from a buffer or found by delayed processing.
+func RecordErrorWithTimestamp(ctx context.Context, err error, t time.Time, tags ...attribute.KeyValue) context.Context {
+	span, ctx := StartTraceWithTimestamp(ctx, ScopedKey("ctx", ptr.String("-")), t, []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}, tags...)
+	defer span.End(trace.WithTimestamp(t), trace.WithStackTrace(true))
+	recordSpanError(span, err, []trace.EventOption{trace.WithTimestamp(t)})
+	return ctx
+}
+
+// RecordErrors records errors from a processing loop on a single span, rather than a span per error
+// as RecordError does. Errors with the same type and message are grouped into one event with an
+// ErrorCountAttribute, and nil errors are skipped.
+func RecordErrors(ctx context.Context, errs []error, tags ...attribute.KeyValue) context.Context {
+	span, ctx := StartTraceWithTimestamp(ctx, ScopedKey("ctx", ptr.String("-")), time.Now(), []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}, tags...)
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return ctx
+	}
+	type errorGroup struct {
+		err   error
+		count int
+	}
+	var groups []*errorGroup
+	index := map[string]*errorGroup{}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		key := reflect.TypeOf(err).String() + ":" + err.Error()
+		if group, ok := index[key]; ok {
+			group.count++
+			continue
+		}
+		group := &errorGroup{err: err, count: 1}
+		index[key] = group
+		groups = append(groups, group)
+	}
+	for _, group := range groups {
+		recordSpanError(span, group.err, []trace.EventOption{trace.WithAttributes(attribute.Int(ErrorCountAttribute, group.count))})
+	}
+	return ctx
+}
+
+// AddAttributes sets attributes on the active span in ctx, such as the span created by a Scout middleware.
+// It does nothing if ctx has no recording span.
+func AddAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// AddEvent adds an event to the active span in ctx. It does nothing if ctx has no recording span.
+func AddEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// RecordErrorOnSpan records `err` on the active span in ctx, such as the span created by a Scout middleware,
+// rather than starting a new span. If ctx has no recording span, it falls back to RecordError.
+func RecordErrorOnSpan(ctx context.Context, err error, tags ...attribute.KeyValue) context.Context {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return RecordError(ctx, err, tags...)
+	}
+	RecordSpanError(span, err, tags...)
+	return ctx
+}
+
 func RecordSpanError(span trace.Span, err error, tags ...attribute.KeyValue) {
+	recordSpanError(span, err, nil, tags...)
+}
+
+func recordSpanError(span trace.Span, err error, opts []trace.EventOption, tags ...attribute.KeyValue) {
+	if !span.IsRecording() {
+		return
+	}
 	if urlErr, ok := err.(*url.Error); ok {
 		span.SetAttributes(attribute.String("Op", urlErr.Op))
 		span.SetAttributes(attribute.String(ErrorURLAttribute, urlErr.URL))
 	}
 	span.SetAttributes(tags...)
+	if attrs := cancellationAttributes(err); attrs != nil {
+		opts = append(opts[:len(opts):len(opts)], trace.WithAttributes(attrs...))
+	}
 	// if this is an error with true stacktrace, then create the event directly since otel doesn't support saving a custom stacktrace
 	var stackErr ErrorWithStack
 	if errors.As(err, &stackErr) {
-		RecordSpanErrorWithStack(span, stackErr)
+		recordSpanErrorWithStack(span, stackErr, opts)
 	} else {
-		span.RecordError(err, trace.WithStackTrace(true))
+		span.RecordError(err, append([]trace.EventOption{trace.WithStackTrace(true)}, opts...)...)
 	}
 }
 
 func RecordSpanErrorWithStack(span trace.Span, err ErrorWithStack) {
+	recordSpanErrorWithStack(span, err, nil)
+}
+
+func recordSpanErrorWithStack(span trace.Span, err ErrorWithStack, opts []trace.EventOption) {
 	stackTrace := fmt.Sprintf("%+v", err.StackTrace())
-	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+	span.AddEvent(semconv.ExceptionEventName, append([]trace.EventOption{trace.WithAttributes(
 		semconv.ExceptionTypeKey.String(reflect.TypeOf(err).String()),
 		semconv.ExceptionMessageKey.String(err.Error()),
 		semconv.ExceptionStacktraceKey.String(stackTrace),
-	))
+	)}, opts...)...)
 }
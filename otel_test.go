@@ -0,0 +1,98 @@
+package scout
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	octrace "go.opencensus.io/trace"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestZipkinExporter(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	useConfig(t, &config{disableOTLP: true, zipkinEndpoint: server.URL})
+	t.Cleanup(resetExporterStats)
+
+	processors, err := newExportProcessors(nil)
+	require.NoError(t, err)
+	require.Len(t, processors, 1)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processors[0]))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	_, span := provider.Tracer("test").Start(context.Background(), "checkout")
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, bodies, 1)
+	assert.Contains(t, bodies[0], `"name":"checkout"`)
+}
+
+func TestOpenCensusBridge(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	useConfig(t, &config{
+		tracerProvider:   sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		openCensusBridge: true,
+		disableOTLP:      true,
+	})
+	prevTracer, prevPropagator, prevOCTracer := loadTracer(), otel.GetTextMapPropagator(), octrace.DefaultTracer
+	defer func() {
+		storeTracer(prevTracer)
+		otel.SetTextMapPropagator(prevPropagator)
+		octrace.DefaultTracer = prevOCTracer
+	}()
+
+	o, err := StartOTLP()
+	require.NoError(t, err)
+	defer o.shutdown(context.Background())
+
+	_, span := octrace.StartSpan(context.Background(), "legacy.query")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "legacy.query", spans[0].Name())
+}
+
+func TestWithSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	useConfig(t, &config{
+		privateTracerProvider: true,
+		disableOTLP:           true,
+		spanProcessors:        []sdktrace.SpanProcessor{recorder},
+		samplingRateMap:       map[trace.SpanKind]float64{trace.SpanKindUnspecified: 1},
+	})
+	prev := loadTracer()
+	defer storeTracer(prev)
+
+	o, err := StartOTLP()
+	require.NoError(t, err)
+	defer o.shutdown(context.Background())
+
+	span, _ := StartTrace(context.Background(), "reconcile")
+	EndTrace(span)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "reconcile", spans[0].Name())
+}
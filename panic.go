@@ -0,0 +1,93 @@
+package scout
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const GoroutineDumpAttribute = "exception.goroutines"
+const GoroutineDumpTruncatedAttribute = "exception.goroutines.truncated"
+
+// maxGoroutineDumpBytes caps the size of a goroutine dump before compression.
+const maxGoroutineDumpBytes = 8 << 20
+
+// WithGoroutineDumps attaches a dump of the stacks of all goroutines to panics recorded with RecordPanic
+// and to panic and fatal logs. Dumps are gzipped and capped at 8MB before compression.
+// Capturing a dump briefly stops the world, so it is only taken for panics and fatal errors.
+func WithGoroutineDumps() Option {
+	return runtimeOption(func(conf *config) {
+		conf.goroutineDumps = true
+	})
+}
+
+// RecordPanic records a value recovered from a panic as an error, attaching a goroutine dump
+// if WithGoroutineDumps is set.
+//
+// For example:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			scout.RecordPanic(ctx, r)
+//		}
+//	}()
+func RecordPanic(ctx context.Context, recovered interface{}, tags ...attribute.KeyValue) context.Context {
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("panic: %+v", recovered)
+	}
+	span, ctx := StartTraceWithTimestamp(ctx, ScopedKey("ctx", ptr.String("-")), time.Now(), []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}, tags...)
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return ctx
+	}
+	span.RecordError(err, trace.WithStackTrace(true), trace.WithAttributes(GoroutineDumpAttributes()...))
+	return ctx
+}
+
+// GoroutineDumpAttributes returns attributes holding a dump of the stacks of all goroutines, gzipped
+// and base64 encoded, for attaching to an error event. It returns nil unless WithGoroutineDumps is set.
+func GoroutineDumpAttributes() []attribute.KeyValue {
+	conf := loadConfig()
+	if !conf.goroutineDumps {
+		return nil
+	}
+	dump, truncated := goroutineDump(maxGoroutineDumpBytes)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(dump); err != nil {
+		logger.Errorf("failed to compress goroutine dump: %s", err)
+		return nil
+	}
+	if err := gz.Close(); err != nil {
+		logger.Errorf("failed to compress goroutine dump: %s", err)
+		return nil
+	}
+	return []attribute.KeyValue{
+		attribute.String(GoroutineDumpAttribute, base64.StdEncoding.EncodeToString(buf.Bytes())),
+		attribute.Bool(GoroutineDumpTruncatedAttribute, truncated),
+	}
+}
+
+// goroutineDump returns the stacks of all goroutines, truncated to limit bytes.
+func goroutineDump(limit int) ([]byte, bool) {
+	buf := make([]byte, min(64<<10, limit))
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n], false
+		}
+		if len(buf) >= limit {
+			return buf[:limit], true
+		}
+		buf = make([]byte, min(2*len(buf), limit))
+	}
+}
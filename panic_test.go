@@ -0,0 +1,46 @@
+package scout
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestRecordPanic(t *testing.T) {
+	recorder := recordSpans(t)
+	useConfig(t, &config{goroutineDumps: true})
+
+	RecordPanic(context.Background(), "boom")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 1)
+	attrs := map[string]string{}
+	for _, kv := range events[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "panic: boom", attrs[string(semconv.ExceptionMessageKey)])
+	assert.Equal(t, "false", attrs[GoroutineDumpTruncatedAttribute])
+
+	data, err := base64.StdEncoding.DecodeString(attrs[GoroutineDumpAttribute])
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	dump, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Contains(t, string(dump), "TestRecordPanic")
+}
+
+func TestGoroutineDumpTruncated(t *testing.T) {
+	dump, truncated := goroutineDump(128)
+	assert.True(t, truncated)
+	assert.Len(t, dump, 128)
+}
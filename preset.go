@@ -0,0 +1,61 @@
+package scout
+
+import (
+	"time"
+)
+
+// envPreset selects a Preset applied before the options passed to Init.
+const envPreset = "SCOUT_PRESET"
+
+// Preset bundles the options suited to an environment.
+type Preset string
+
+const (
+	// PresetDevelopment samples everything, writes spans to the console and exports them quickly.
+	PresetDevelopment Preset = "development"
+	// PresetStaging samples half of the traces and scrubs secrets from the telemetry.
+	PresetStaging Preset = "staging"
+	// PresetProduction samples a tenth of the traces, scrubs secrets from the telemetry and exports
+	// spans and metrics in larger batches.
+	PresetProduction Preset = "production"
+)
+
+var presets = map[Preset][]Option{
+	PresetDevelopment: {
+		WithSamplingRate(1),
+		WithMetricSamplingRate(1),
+		WithConsoleExporter(),
+		WithExportBatching(100*time.Millisecond, 32),
+	},
+	PresetStaging: {
+		WithSamplingRate(0.5),
+		WithMetricSamplingRate(1),
+		WithDataScrubbing(),
+	},
+	PresetProduction: {
+		WithSamplingRate(0.1),
+		WithMetricSamplingRate(1),
+		WithDataScrubbing(),
+		WithExportBatching(5*time.Second, 512),
+		WithMetricBatching(10 * time.Second),
+	},
+}
+
+// WithPreset applies the options of preset and sets it as the deployment environment, so environments
+// share one configuration. The preset can also be selected with the SCOUT_PRESET environment variable.
+// Options passed after the preset override it:
+//
+//	scout.Init(scout.WithPreset(scout.PresetProduction), scout.WithSamplingRate(0.2))
+func WithPreset(preset Preset) Option {
+	return option(func(conf *config) {
+		opts, ok := presets[preset]
+		if !ok {
+			logger.Warnf("ignoring unknown scout preset %q", preset)
+			return
+		}
+		WithEnvironment(string(preset)).apply(conf)
+		for _, opt := range opts {
+			opt.apply(conf)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package scout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithPreset(t *testing.T) {
+	c := &config{}
+	WithPreset(PresetProduction).apply(c)
+	WithSamplingRate(0.2).apply(c)
+	assert.Equal(t, map[trace.SpanKind]float64{trace.SpanKindUnspecified: 0.2}, c.samplingRateMap)
+	assert.True(t, c.dataScrubbing)
+	assert.Equal(t, 5*time.Second, c.exportBatchTimeout)
+	assert.Equal(t, 10*time.Second, c.metricBatchInterval)
+	assert.Contains(t, c.resourceAttributes, semconv.DeploymentEnvironmentKey.String("production"))
+
+	c = &config{}
+	WithPreset(PresetDevelopment).apply(c)
+	assert.True(t, c.consoleExporter)
+	assert.False(t, c.dataScrubbing)
+
+	c = &config{}
+	WithPreset("qa").apply(c)
+	assert.Equal(t, &config{}, c)
+}
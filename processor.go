@@ -0,0 +1,162 @@
+package scout
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanFilter transforms an ended span before export. Returning nil drops the span.
+type spanFilter func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan
+
+// filterProcessor applies filters to ended spans before handing them to the next processor,
+// counting the spans it hands over in the stats of each exporter the next processor exports to.
+type filterProcessor struct {
+	next    sdktrace.SpanProcessor
+	filters []spanFilter
+	stats   []*exporterStats
+}
+
+var _ sdktrace.SpanProcessor = filterProcessor{}
+
+func (p filterProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p filterProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	for _, filter := range p.filters {
+		if s = filter(s); s == nil {
+			return
+		}
+		// spans that were not sampled are only recorded so slow spans can be force sampled,
+		// the remaining filters are wasted on spans that are not exported
+		if !s.SpanContext().IsSampled() {
+			break
+		}
+	}
+	if s.SpanContext().IsSampled() {
+		for _, stats := range p.stats {
+			stats.queued.Add(1)
+		}
+	}
+	p.next.OnEnd(s)
+}
+
+func (p filterProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+func (p filterProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+// spanFilters returns the filters configured for the export pipeline.
+func spanFilters() []spanFilter {
+	conf := loadConfig()
+	var filters []spanFilter
+	// flagging slow spans runs first as it may force unsampled spans to be sampled
+	if len(conf.slowSpanThresholds) > 0 {
+		filters = append(filters, slowSpanFilter(conf.slowSpanThresholds, conf.slowSpanSampling))
+	}
+	if len(conf.enrichmentRules) > 0 {
+		filters = append(filters, enrichmentFilter(conf.enrichmentRules))
+	}
+	if len(conf.attributeAllowlist) > 0 {
+		filters = append(filters, attributeFilter(func(key attribute.Key) bool {
+			return conf.attributeAllowlist[key] || isRequiredAttribute(key)
+		}))
+	}
+	if len(conf.attributeDenylist) > 0 {
+		filters = append(filters, attributeFilter(func(key attribute.Key) bool {
+			return !conf.attributeDenylist[key]
+		}))
+	}
+	if conf.dataScrubbing {
+		keys := append(append([]attribute.Key{}, defaultScrubbedAttributes...), conf.scrubbedAttributes...)
+		filters = append(filters, dataScrubbingFilter(keys, conf.scrubPatterns))
+	}
+	if conf.anonymization {
+		filters = append(filters, anonymizationFilter(conf.anonymizationSalt))
+	}
+	// the hook runs last so it sees exactly what would be exported
+	if conf.beforeSendSpan != nil {
+		filters = append(filters, func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+			if !conf.beforeSendSpan(s) {
+				return nil
+			}
+			return s
+		})
+	}
+	return filters
+}
+
+// filteredSpan overrides the span context, attributes, events and status of an ended span.
+type filteredSpan struct {
+	sdktrace.ReadOnlySpan
+	spanContext trace.SpanContext
+	attributes  []attribute.KeyValue
+	events      []sdktrace.Event
+	status      sdktrace.Status
+}
+
+func newFilteredSpan(s sdktrace.ReadOnlySpan) *filteredSpan {
+	if f, ok := s.(*filteredSpan); ok {
+		return f
+	}
+	return &filteredSpan{
+		ReadOnlySpan: s,
+		spanContext:  s.SpanContext(),
+		attributes:   s.Attributes(),
+		events:       s.Events(),
+		status:       s.Status(),
+	}
+}
+
+func (s *filteredSpan) SpanContext() trace.SpanContext {
+	return s.spanContext
+}
+
+func (s *filteredSpan) Attributes() []attribute.KeyValue {
+	return s.attributes
+}
+
+func (s *filteredSpan) Events() []sdktrace.Event {
+	return s.events
+}
+
+func (s *filteredSpan) Status() sdktrace.Status {
+	return s.status
+}
+
+// mapAttributes rewrites the span and event attributes. Returning false from fn removes the attribute.
+func (s *filteredSpan) mapAttributes(fn func(kv attribute.KeyValue) (attribute.KeyValue, bool)) {
+	s.attributes = mapAttributes(s.attributes, fn)
+	events := make([]sdktrace.Event, len(s.events))
+	for i, event := range s.events {
+		event.Attributes = mapAttributes(event.Attributes, fn)
+		events[i] = event
+	}
+	s.events = events
+}
+
+func mapAttributes(attrs []attribute.KeyValue, fn func(kv attribute.KeyValue) (attribute.KeyValue, bool)) []attribute.KeyValue {
+	mapped := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if kv, ok := fn(kv); ok {
+			mapped = append(mapped, kv)
+		}
+	}
+	return mapped
+}
+
+func attributeFilter(keep func(key attribute.Key) bool) spanFilter {
+	return func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		filtered := newFilteredSpan(s)
+		filtered.mapAttributes(func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+			return kv, keep(kv.Key)
+		})
+		return filtered
+	}
+}
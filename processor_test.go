@@ -0,0 +1,65 @@
+package scout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAttributeFilter(t *testing.T) {
+	span := tracetest.SpanStub{
+		Name: "test",
+		Attributes: []attribute.KeyValue{
+			attribute.String("user.email", "x@example.com"),
+			attribute.String("http.method", "GET"),
+			attribute.String(ProjectIDAttribute, "1"),
+		},
+		Events: []sdktrace.Event{{
+			Name: LogEvent,
+			Attributes: []attribute.KeyValue{
+				attribute.String(LogMessageAttribute, "hello"),
+				attribute.String("user.email", "x@example.com"),
+			},
+		}},
+	}.Snapshot()
+
+	tests := map[string]struct {
+		opts           []Option
+		attributes     []attribute.KeyValue
+		eventAttribute []attribute.KeyValue
+	}{
+		"denylist": {
+			opts: []Option{WithAttributeDenylist("user.email")},
+			attributes: []attribute.KeyValue{
+				attribute.String("http.method", "GET"),
+				attribute.String(ProjectIDAttribute, "1"),
+			},
+			eventAttribute: []attribute.KeyValue{attribute.String(LogMessageAttribute, "hello")},
+		},
+		"allowlist": {
+			opts:           []Option{WithAttributeAllowlist("http.method")},
+			attributes:     []attribute.KeyValue{attribute.String("http.method", "GET"), attribute.String(ProjectIDAttribute, "1")},
+			eventAttribute: []attribute.KeyValue{attribute.String(LogMessageAttribute, "hello")},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &config{}
+			for _, opt := range tt.opts {
+				opt.apply(c)
+			}
+			useConfig(t, c)
+
+			var filtered sdktrace.ReadOnlySpan = span
+			for _, filter := range spanFilters() {
+				filtered = filter(filtered)
+			}
+			assert.Equal(t, tt.attributes, filtered.Attributes())
+			assert.Equal(t, tt.eventAttribute, filtered.Events()[0].Attributes)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package scout
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ProfileEvent = "profile"
+const ProfileTypeAttribute = "profile.type"
+const ProfileFormatAttribute = "profile.format"
+const ProfileDataAttribute = "profile.data"
+const ProfileDurationAttribute = "profile.duration"
+
+// ProfileType is the kind of profile attached to a profile event.
+type ProfileType string
+
+const (
+	ProfileCPU  ProfileType = "cpu"
+	ProfileHeap ProfileType = "heap"
+	// ProfileBlock and ProfileMutex are cumulative since profiling was enabled
+	ProfileBlock ProfileType = "block"
+	ProfileMutex ProfileType = "mutex"
+)
+
+const (
+	// profileFormatPprof is gzipped pprof protobuf, encoded as base64 in the event
+	profileFormatPprof = "pprof"
+	// profileCPUDuration bounds the time spent CPU profiling per profiling interval
+	profileCPUDuration = 10 * time.Second
+	// maxProfileBytes caps the size of a profile attached to an event
+	maxProfileBytes = 1 << 20
+)
+
+// WithProfiling continuously profiles the service, capturing a CPU profile of up to 10 seconds and
+// a heap profile every interval. Profiles are recorded as Scout events so they can be viewed
+// alongside traces. Overhead is bounded by the interval, so an interval of a minute or more is
+// recommended. CPU profiles are skipped while the application is running its own CPU profile.
+func WithProfiling(interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.profilingInterval = interval
+	})
+}
+
+// WithBlockProfiling enables the block profile, sampling on average one blocking event per rate
+// nanoseconds spent blocked, and uploads it every interval to diagnose contention that shows up
+// as tail latency. See runtime.SetBlockProfileRate. The rate is reset when Scout stops.
+func WithBlockProfiling(rate int, interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.blockProfileRate = rate
+		conf.blockProfileInterval = interval
+	})
+}
+
+// WithMutexProfiling enables the mutex profile, sampling on average 1/fraction of mutex contention
+// events, and uploads it every interval. See runtime.SetMutexProfileFraction.
+// The previous fraction is restored when Scout stops.
+func WithMutexProfiling(fraction int, interval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.mutexProfileFraction = fraction
+		conf.mutexProfileInterval = interval
+	})
+}
+
+func startContentionProfiler() {
+	conf := loadConfig()
+	if conf.blockProfileRate > 0 && conf.blockProfileInterval > 0 {
+		runtime.SetBlockProfileRate(conf.blockProfileRate)
+		onStopWorkers(func() { runtime.SetBlockProfileRate(0) })
+		startPeriodic(conf.blockProfileInterval, func(ctx context.Context) {
+			captureProfile(ctx, ProfileBlock)
+		})
+	}
+	if conf.mutexProfileFraction > 0 && conf.mutexProfileInterval > 0 {
+		prev := runtime.SetMutexProfileFraction(conf.mutexProfileFraction)
+		onStopWorkers(func() { runtime.SetMutexProfileFraction(prev) })
+		startPeriodic(conf.mutexProfileInterval, func(ctx context.Context) {
+			captureProfile(ctx, ProfileMutex)
+		})
+	}
+}
+
+func startProfiler() {
+	conf := loadConfig()
+	if conf.profilingInterval <= 0 {
+		return
+	}
+	startPeriodic(conf.profilingInterval, func(ctx context.Context) {
+		captureCPUProfile(ctx, min(profileCPUDuration, conf.profilingInterval/2))
+		captureProfile(ctx, ProfileHeap)
+	})
+}
+
+func captureCPUProfile(ctx context.Context, duration time.Duration) {
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		logger.Debugf("skipping cpu profile: %s", err)
+		return
+	}
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+	pprof.StopCPUProfile()
+	recordProfile(context.Background(), ProfileCPU, buf.Bytes(), attribute.Float64(ProfileDurationAttribute, time.Since(start).Seconds()))
+}
+
+// captureProfile records a snapshot of one of the runtime/pprof profiles, such as the heap profile.
+func captureProfile(ctx context.Context, profileType ProfileType, tags ...attribute.KeyValue) {
+	profile := pprof.Lookup(string(profileType))
+	if profile == nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := profile.WriteTo(&buf, 0); err != nil {
+		logger.Errorf("failed to capture %s profile: %s", profileType, err)
+		return
+	}
+	recordProfile(ctx, profileType, buf.Bytes(), tags...)
+}
+
+// recordProfile attaches a pprof profile to a profile event, associated with the session and trace in ctx.
+// Profiles larger than maxProfileBytes are discarded.
+func recordProfile(ctx context.Context, profileType ProfileType, data []byte, tags ...attribute.KeyValue) {
+	recordProfileData(ctx, profileType, profileFormatPprof, data, tags...)
+}
+
+func recordProfileData(ctx context.Context, profileType ProfileType, format string, data []byte, tags ...attribute.KeyValue) {
+	if len(data) == 0 {
+		return
+	}
+	if len(data) > maxProfileBytes {
+		logger.Warnf("discarding %s profile of %d bytes, above the limit of %d bytes", profileType, len(data), maxProfileBytes)
+		return
+	}
+	span, _ := startInternalTrace(ctx, ScopedKey("profile", ptr.String("-")), tags...)
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(ProfileEvent, trace.WithAttributes(
+		attribute.String(ProfileTypeAttribute, string(profileType)),
+		attribute.String(ProfileFormatAttribute, format),
+		attribute.String(ProfileDataAttribute, base64.StdEncoding.EncodeToString(data)),
+	))
+}
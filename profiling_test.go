@@ -0,0 +1,50 @@
+package scout
+
+import (
+	"context"
+	"encoding/base64"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureProfile(t *testing.T) {
+	recorder := recordSpans(t)
+
+	captureProfile(context.Background(), ProfileHeap)
+	captureCPUProfile(context.Background(), 10*time.Millisecond)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for i, profileType := range []ProfileType{ProfileHeap, ProfileCPU} {
+		events := spans[i].Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, ProfileEvent, events[0].Name)
+		attrs := map[string]string{}
+		for _, kv := range events[0].Attributes {
+			attrs[string(kv.Key)] = kv.Value.AsString()
+		}
+		assert.Equal(t, string(profileType), attrs[ProfileTypeAttribute])
+		data, err := base64.StdEncoding.DecodeString(attrs[ProfileDataAttribute])
+		require.NoError(t, err)
+		// pprof profiles are gzipped
+		assert.Equal(t, []byte{0x1f, 0x8b}, data[:2])
+	}
+}
+
+func TestContentionProfiler(t *testing.T) {
+	recorder := recordSpans(t)
+	useConfig(t, &config{mutexProfileFraction: 5, mutexProfileInterval: time.Hour})
+	before := runtime.SetMutexProfileFraction(-1)
+
+	startWorkers()
+	startContentionProfiler()
+	assert.Equal(t, 5, runtime.SetMutexProfileFraction(-1))
+	require.Eventually(t, func() bool { return len(recorder.Ended()) == 1 }, time.Second, time.Millisecond)
+	stopWorkers()
+
+	assert.Equal(t, before, runtime.SetMutexProfileFraction(-1))
+}
@@ -0,0 +1,161 @@
+package scout
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Propagator propagates the Scout session secure ID and request ID in the X-Scout-Request header,
+// so any OpenTelemetry instrumented client or server carries the Scout identifiers.
+type Propagator struct{}
+
+var _ propagation.TextMapPropagator = Propagator{}
+
+// Inject sets the X-Scout-Request header from the identifiers in ctx.
+func (Propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	if value := HeaderValue(ctx); value != "" {
+		carrier.Set(RequestTracerHeader, value)
+	}
+}
+
+// Extract reads the X-Scout-Request header into ctx.
+func (Propagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sessionSecureID, requestID, err := ExtractIdsFromRequest(carrier.Get(RequestTracerHeader))
+	if err != nil {
+		return ctx
+	}
+	ctx = ContextWithSessionID(ctx, sessionSecureID)
+	ctx = ContextWithRequestID(ctx, requestID)
+	return ctx
+}
+
+// Fields returns the headers used by the propagator.
+func (Propagator) Fields() []string {
+	return []string{RequestTracerHeader}
+}
+
+// NewPropagator returns a composite of the W3C trace context, W3C baggage and Scout propagators,
+// including the Datadog propagator when WithDatadogPropagation is set.
+func NewPropagator() propagation.TextMapPropagator {
+	conf := loadConfig()
+	propagators := []propagation.TextMapPropagator{
+		propagation.TraceContext{},
+		propagation.Baggage{},
+		Propagator{},
+	}
+	if conf.datadogPropagation {
+		propagators = append(propagators, DatadogPropagator{})
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...)
+}
+
+// globalPropagator is the propagator registered with otel.SetTextMapPropagator on start. It builds the
+// propagators of NewPropagator on each call, so reconfiguring WithDatadogPropagation applies to every
+// user of the global propagator, such as otelhttp, and keeps the propagator the application registered
+// before, if any, so its headers are still propagated.
+type globalPropagator struct {
+	previous propagation.TextMapPropagator
+}
+
+// registerPropagator sets globalPropagator as the global propagator, composed with the propagator
+// registered by the application.
+func registerPropagator() {
+	previous := otel.GetTextMapPropagator()
+	if p, ok := previous.(globalPropagator); ok {
+		// started again, keep the application's propagator rather than wrapping ours
+		previous = p.previous
+	} else if previous != nil && len(previous.Fields()) == 0 {
+		// the default propagator propagates nothing, and would delegate back to ours once registered
+		previous = nil
+	}
+	otel.SetTextMapPropagator(globalPropagator{previous: previous})
+}
+
+func (p globalPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	if p.previous != nil {
+		p.previous.Inject(ctx, carrier)
+	}
+	NewPropagator().Inject(ctx, carrier)
+}
+
+func (p globalPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if p.previous != nil {
+		ctx = p.previous.Extract(ctx, carrier)
+	}
+	return NewPropagator().Extract(ctx, carrier)
+}
+
+func (p globalPropagator) Fields() []string {
+	fields := NewPropagator().Fields()
+	if p.previous == nil {
+		return fields
+	}
+	for _, field := range p.previous.Fields() {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Carrier stores propagated values in a custom transport, such as the metadata of a queue message,
+// a webhook payload or a job's arguments. propagation.MapCarrier and propagation.HeaderCarrier implement it.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// carrierAdapter adapts a Carrier to a propagation.TextMapCarrier. None of the propagators returned
+// by NewPropagator enumerate the keys of a carrier.
+type carrierAdapter struct {
+	Carrier
+}
+
+func (carrierAdapter) Keys() []string {
+	return nil
+}
+
+// Inject sets the Scout session and trace context of ctx on carrier, using the propagators returned by
+// NewPropagator. For example, to propagate through a job queue:
+//
+//	job.Metadata = map[string]string{}
+//	scout.Inject(ctx, propagation.MapCarrier(job.Metadata))
+func Inject(ctx context.Context, carrier Carrier) {
+	if tm, ok := carrier.(propagation.TextMapCarrier); ok {
+		NewPropagator().Inject(ctx, tm)
+		return
+	}
+	NewPropagator().Inject(ctx, carrierAdapter{carrier})
+}
+
+// Extract returns a copy of ctx carrying the Scout session and trace context set on carrier by Inject.
+// Spans started from the returned context continue the trace of the injecting service.
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	if tm, ok := carrier.(propagation.TextMapCarrier); ok {
+		return NewPropagator().Extract(ctx, tm)
+	}
+	return NewPropagator().Extract(ctx, carrierAdapter{carrier})
+}
+
+// HeaderValue returns the X-Scout-Request header value carrying the session and request IDs of ctx,
+// or an empty string if ctx has neither, for clients whose headers can't be set with InjectHTTPHeaders.
+func HeaderValue(ctx context.Context) string {
+	sessionSecureID, requestID := GetSessionID(ctx), GetRequestID(ctx)
+	if sessionSecureID == "" && requestID == "" {
+		return ""
+	}
+	return sessionSecureID + "/" + requestID
+}
+
+// InjectHTTPHeaders sets the X-Scout-Request and trace context headers of ctx on h, for HTTP clients,
+// webhooks and third-party SDKs that aren't sent through an instrumented transport. For example:
+//
+//	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, body)
+//	scout.InjectHTTPHeaders(ctx, req.Header)
+func InjectHTTPHeaders(ctx context.Context, h http.Header) {
+	NewPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
@@ -0,0 +1,112 @@
+package scout
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagator(t *testing.T) {
+	ctx := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "request")
+	header := http.Header{}
+	Propagator{}.Inject(ctx, propagation.HeaderCarrier(header))
+	assert.Equal(t, "session/request", header.Get(RequestTracerHeader))
+
+	extracted := Propagator{}.Extract(context.Background(), propagation.HeaderCarrier(header))
+	assert.Equal(t, "session", GetSessionID(extracted))
+	assert.Equal(t, "request", GetRequestID(extracted))
+
+	empty := http.Header{}
+	Propagator{}.Inject(context.Background(), propagation.HeaderCarrier(empty))
+	assert.Empty(t, empty)
+}
+
+// payloadCarrier is a Carrier that is not a propagation.TextMapCarrier.
+type payloadCarrier struct {
+	values map[string]string
+}
+
+func (c payloadCarrier) Get(key string) string {
+	return c.values[key]
+}
+
+func (c payloadCarrier) Set(key, value string) {
+	c.values[key] = value
+}
+
+func TestInjectExtract(t *testing.T) {
+	recordSpans(t)
+	ctx := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "request")
+	span, ctx := StartTrace(ctx, "producer")
+	defer EndTrace(span)
+
+	carrier := payloadCarrier{values: map[string]string{}}
+	Inject(ctx, carrier)
+	assert.Equal(t, "session/request", carrier.values[RequestTracerHeader])
+	assert.NotEmpty(t, carrier.values["traceparent"])
+
+	extracted := Extract(context.Background(), carrier)
+	assert.Equal(t, "session", GetSessionID(extracted))
+	assert.Equal(t, "request", GetRequestID(extracted))
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(extracted).TraceID())
+}
+
+func TestInjectHTTPHeaders(t *testing.T) {
+	recordSpans(t)
+	ctx := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "request")
+	assert.Equal(t, "session/request", HeaderValue(ctx))
+	assert.Empty(t, HeaderValue(context.Background()))
+
+	span, ctx := StartTrace(ctx, "webhook")
+	defer EndTrace(span)
+	header := http.Header{}
+	InjectHTTPHeaders(ctx, header)
+	assert.Equal(t, "session/request", header.Get(RequestTracerHeader))
+	assert.Contains(t, header.Get("Traceparent"), span.SpanContext().TraceID().String())
+}
+
+func TestRegisterPropagator(t *testing.T) {
+	useConfig(t, &config{})
+	prev := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(prev)
+
+	otel.SetTextMapPropagator(DatadogPropagator{})
+	registerPropagator()
+	registerPropagator()
+	global, ok := otel.GetTextMapPropagator().(globalPropagator)
+	require.True(t, ok)
+	assert.Equal(t, DatadogPropagator{}, global.previous, "the application's propagator is composed once")
+	assert.Contains(t, global.Fields(), RequestTracerHeader)
+	assert.Contains(t, global.Fields(), datadogTraceIDHeader)
+
+	traceID, _ := trace.TraceIDFromHex("0000000000000000000000000000002a")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled})
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+	assert.Equal(t, "42", carrier.Get(datadogTraceIDHeader))
+	assert.NotEmpty(t, carrier.Get("traceparent"))
+}
+
+func TestRegisterPropagatorWithTracerProvider(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+	useConfig(t, &config{tracerProvider: provider, disableOTLP: true})
+	prev, prevTracer := otel.GetTextMapPropagator(), loadTracer()
+	defer func() {
+		otel.SetTextMapPropagator(prev)
+		storeTracer(prevTracer)
+	}()
+
+	otel.SetTextMapPropagator(propagation.Baggage{})
+	o, err := StartOTLP()
+	require.NoError(t, err)
+	defer o.shutdown(context.Background())
+	assert.Equal(t, globalPropagator{previous: propagation.Baggage{}}, otel.GetTextMapPropagator())
+}
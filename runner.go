@@ -0,0 +1,45 @@
+package scout
+
+import (
+	"context"
+	"sync"
+)
+
+// Runner starts Scout for an application managing its own lifecycle, with oklog/run or errgroup, rather
+// than relying on Scout stopping itself on SIGINT and SIGTERM. Scout is started when Runner is called, so
+// telemetry recorded while the other actors start is kept. run blocks until ctx is done or interrupt is
+// called, and then stops Scout, returning the last error flushing the buffered telemetry:
+//
+//	var g run.Group
+//	g.Add(scout.Runner(ctx, scout.WithProjectID(id)))
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	run, _ := scout.Runner(ctx, scout.WithProjectID(id))
+//	g.Go(run)
+//
+// With errgroup, ctx must be done once the application stops, for example by deriving it from
+// signal.NotifyContext.
+func Runner(ctx context.Context, opts ...Option) (run func() error, interrupt func(error)) {
+	start(opts)
+	done := make(chan struct{})
+	var once sync.Once
+	run = func() error {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		return Shutdown(context.WithoutCancel(ctx))
+	}
+	interrupt = func(error) {
+		once.Do(func() { close(done) })
+	}
+	return run, interrupt
+}
+
+// Shutdown stops Scout like Stop, but stops waiting for the buffered telemetry to be flushed once ctx is
+// done, so Scout fits within the shutdown deadline of the application. It returns the last error
+// flushing or exporting spans.
+func Shutdown(ctx context.Context) error {
+	interrupt()
+	return shutdown(ctx).LastError
+}
@@ -0,0 +1,39 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunner(t *testing.T) {
+	run, interrupt := Runner(context.Background())
+	if !IsRunning() {
+		t.Fatalf("[Runner] expected scout to be running once the runner is created")
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- run() }()
+
+	select {
+	case <-errc:
+		t.Fatalf("[Runner] expected run to block until interrupted")
+	case <-time.After(10 * time.Millisecond):
+	}
+	interrupt(errors.New("stopping"))
+	interrupt(nil)
+	<-errc
+	if IsRunning() {
+		t.Fatalf("[Runner] expected scout to be stopped once run returns")
+	}
+}
+
+func TestRunnerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run, _ := Runner(ctx)
+	cancel()
+	_ = run()
+	if IsRunning() {
+		t.Fatalf("[Runner] expected scout to be stopped once its context is done")
+	}
+}
@@ -0,0 +1,134 @@
+package scout
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultRuntimeMetrics are the runtime/metrics series collected by WithRuntimeMetrics when no allowlist is given.
+var DefaultRuntimeMetrics = []string{
+	"/sched/goroutines:goroutines",
+	"/sched/latencies:seconds",
+	"/gc/cycles/automatic:gc-cycles",
+	"/gc/cycles/forced:gc-cycles",
+	"/gc/pauses:seconds",
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/heap/unused:bytes",
+	"/memory/classes/heap/released:bytes",
+	"/memory/classes/total:bytes",
+}
+
+// runtimeMetricQuantiles are recorded for histogram series, as <name>.p50 and so on.
+var runtimeMetricQuantiles = []struct {
+	suffix   string
+	quantile float64
+}{{".p50", 0.5}, {".p90", 0.9}, {".p99", 0.99}}
+
+// WithRuntimeMetrics records series of the runtime/metrics package every interval, such as scheduler
+// latencies, GC cycles by cause and memory classes. names is an allowlist of series names; it defaults
+// to DefaultRuntimeMetrics. Histogram series are recorded as their 50th, 90th and 99th percentiles.
+// Cumulative series are recorded as their running totals.
+func WithRuntimeMetrics(interval time.Duration, names ...string) Option {
+	return option(func(conf *config) {
+		conf.runtimeMetricsPeriod = interval
+		conf.runtimeMetrics = names
+	})
+}
+
+func startRuntimeMetrics() {
+	conf := loadConfig()
+	if conf.runtimeMetricsPeriod <= 0 {
+		return
+	}
+	samples := runtimeMetricSamples(conf.runtimeMetrics)
+	if len(samples) == 0 {
+		return
+	}
+	startPeriodic(conf.runtimeMetricsPeriod, func(ctx context.Context) {
+		recordRuntimeMetrics(ctx, samples)
+	})
+}
+
+// runtimeMetricSamples returns samples for the supported series in names.
+func runtimeMetricSamples(names []string) []metrics.Sample {
+	if len(names) == 0 {
+		names = DefaultRuntimeMetrics
+	}
+	supported := map[string]bool{}
+	for _, desc := range metrics.All() {
+		supported[desc.Name] = true
+	}
+	samples := make([]metrics.Sample, 0, len(names))
+	for _, name := range names {
+		if !supported[name] {
+			logger.Warnf("ignoring unsupported runtime metric %s", name)
+			continue
+		}
+		samples = append(samples, metrics.Sample{Name: name})
+	}
+	return samples
+}
+
+func recordRuntimeMetrics(ctx context.Context, samples []metrics.Sample) {
+	metrics.Read(samples)
+	span, _ := startInternalTrace(ctx, ScopedKey("runtime-metrics", ptr.String("-")))
+	defer EndTrace(span)
+	if !span.IsRecording() {
+		return
+	}
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			addGaugeEvent(span, sample.Name, float64(sample.Value.Uint64()))
+		case metrics.KindFloat64:
+			addGaugeEvent(span, sample.Name, sample.Value.Float64())
+		case metrics.KindFloat64Histogram:
+			histogram := sample.Value.Float64Histogram()
+			for _, q := range runtimeMetricQuantiles {
+				if value, ok := histogramQuantile(histogram, q.quantile); ok {
+					addGaugeEvent(span, sample.Name+q.suffix, value)
+				}
+			}
+		}
+	}
+}
+
+// addGaugeEvent adds a metric event holding the last value of a gauge to span.
+func addGaugeEvent(span trace.Span, name string, value float64, tags ...attribute.KeyValue) {
+	attrs := append([]attribute.KeyValue{
+		attribute.String(MetricEventName, name),
+		attribute.Float64(MetricEventValue, value),
+		attribute.String(MetricEventType, "gauge"),
+	}, tags...)
+	span.AddEvent(MetricEvent, trace.WithAttributes(attrs...))
+}
+
+// histogramQuantile estimates a quantile of a runtime/metrics histogram as the upper bound of the bucket
+// containing it, or its lower bound for the unbounded last bucket. ok is false for an empty histogram.
+func histogramQuantile(h *metrics.Float64Histogram, quantile float64) (float64, bool) {
+	var total uint64
+	for _, count := range h.Counts {
+		total += count
+	}
+	if total == 0 {
+		return 0, false
+	}
+	rank := uint64(math.Ceil(quantile * float64(total)))
+	var seen uint64
+	for i, count := range h.Counts {
+		seen += count
+		if seen >= rank {
+			if upper := h.Buckets[i+1]; !math.IsInf(upper, 1) {
+				return upper, true
+			}
+			return h.Buckets[i], true
+		}
+	}
+	return h.Buckets[len(h.Buckets)-2], true
+}
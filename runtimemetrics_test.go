@@ -0,0 +1,46 @@
+package scout
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{5, 4, 1},
+		Buckets: []float64{0, 1, 2, math.Inf(1)},
+	}
+	tests := map[float64]float64{0.5: 1, 0.9: 2, 0.99: 2}
+	for quantile, expected := range tests {
+		value, ok := histogramQuantile(h, quantile)
+		require.True(t, ok)
+		assert.Equal(t, expected, value, quantile)
+	}
+	_, ok := histogramQuantile(&metrics.Float64Histogram{Counts: []uint64{0}, Buckets: []float64{0, 1}}, 0.5)
+	assert.False(t, ok)
+}
+
+func TestRecordRuntimeMetrics(t *testing.T) {
+	recorder := recordSpans(t)
+
+	samples := runtimeMetricSamples([]string{"/sched/goroutines:goroutines", "/gc/pauses:seconds", "/not/a/metric:bytes"})
+	require.Len(t, samples, 2)
+	recordRuntimeMetrics(context.Background(), samples)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	names := map[string]bool{}
+	for _, event := range spans[0].Events() {
+		for _, kv := range event.Attributes {
+			if kv.Key == MetricEventName {
+				names[kv.Value.AsString()] = true
+			}
+		}
+	}
+	assert.True(t, names["/sched/goroutines:goroutines"])
+}
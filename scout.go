@@ -2,39 +2,100 @@ package scout
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"regexp"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 )
 
 type config struct {
-	otelEndpoint       string
-	projectID          string
-	resourceAttributes []attribute.KeyValue
-	metricSamplingRate float64
-	samplingRateMap    map[trace.SpanKind]float64
+	otelEndpoint          string
+	otelEndpointSet       bool
+	projectID             string
+	resourceAttributes    []attribute.KeyValue
+	metricSamplingRate    float64
+	samplingRateMap       map[trace.SpanKind]float64
+	memoryLimit           int64
+	dropPolicy            DropPolicy
+	signalPriority        []Signal
+	compression           Compression
+	disableOTLP           bool
+	consoleExporter       bool
+	exportBatchTimeout    time.Duration
+	exportBatchSize       int
+	disableVCSAttributes  bool
+	zipkinEndpoint        string
+	datadogAgentEndpoint  string
+	datadogPropagation    bool
+	openCensusBridge      bool
+	tracerProvider        *sdktrace.TracerProvider
+	privateTracerProvider bool
+	spanProcessors        []sdktrace.SpanProcessor
+	attributeAllowlist    map[attribute.Key]bool
+	attributeDenylist     map[attribute.Key]bool
+	dataScrubbing         bool
+	scrubPatterns         []*regexp.Regexp
+	scrubbedAttributes    []attribute.Key
+	beforeSendSpan        func(sdktrace.ReadOnlySpan) bool
+	anonymization         bool
+	anonymizationSalt     string
+	redactedQueryParams   []string
+	heartbeatInterval     time.Duration
+	failoverEndpoints     []string
+	spanLimits            *sdktrace.SpanLimits
+	idGenerator           sdktrace.IDGenerator
+	profilingInterval     time.Duration
+	goroutineDumps        bool
+	heapWatchdogThreshold float64
+	blockProfileRate      int
+	blockProfileInterval  time.Duration
+	mutexProfileFraction  int
+	mutexProfileInterval  time.Duration
+	slowRequestThreshold  time.Duration
+	exportTimeout         time.Duration
+	runtimeMetrics        []string
+	runtimeMetricsPeriod  time.Duration
+	cgroupMetricsPeriod   time.Duration
+	slowSpanThresholds    []slowSpanThreshold
+	slowSpanSampling      bool
+	apdexThreshold        time.Duration
+	apdexPeriod           time.Duration
+	alertWatchers         []alertWatcher
+	alertInterval         time.Duration
+	enrichmentRules       []enrichmentRule
+	generateRequestIDs    bool
+	requestHeaders        []string
+	responseHeaders       []string
+	redactedHeaders       []string
+	statusClassifier      StatusClassifier
+	ignoreCancellations   bool
+	metricBatchInterval   time.Duration
+	lowercaseMetricNames  bool
+	logSpanEvents         bool
+	logBatchInterval      time.Duration
+	logBatchSize          int
+	logMessageMaxLength   int
+	logAttributeMaxLength int
 }
 
 var (
 	interruptChan chan bool
 	signalChan    chan os.Signal
-	conf          = &config{
-		otelEndpoint:       OTLPDefaultEndpoint,
-		metricSamplingRate: 1.,
-		samplingRateMap: map[trace.SpanKind]float64{
-			trace.SpanKindUnspecified: 1.,
-		},
-	}
 )
 
 type Option interface {
@@ -48,13 +109,13 @@ func (fn option) apply(conf *config) {
 }
 
 func WithProjectID(projectID string) Option {
-	return option(func(conf *config) {
+	return runtimeOption(func(conf *config) {
 		conf.projectID = projectID
 	})
 }
 
 func WithMetricSamplingRate(samplingRate float64) Option {
-	return option(func(conf *config) {
+	return runtimeOption(func(conf *config) {
 		conf.metricSamplingRate = samplingRate
 	})
 }
@@ -73,6 +134,106 @@ func WithSamplingRateMap(rates map[trace.SpanKind]float64) Option {
 	})
 }
 
+// WithZipkinEndpoint exports spans in Zipkin format to the collector url, eg: http://localhost:9411/api/v2/spans.
+// Spans are still exported over OTLP unless WithoutOTLPExporter is also set.
+func WithZipkinEndpoint(url string) Option {
+	return option(func(conf *config) {
+		conf.zipkinEndpoint = url
+	})
+}
+
+// WithoutOTLPExporter disables the OTLP exporter, for use with an alternative exporter such as Zipkin.
+func WithoutOTLPExporter() Option {
+	return option(func(conf *config) {
+		conf.disableOTLP = true
+	})
+}
+
+// WithExportBatching sets how long spans are buffered before being exported, 1 second by default, and the
+// maximum number of spans exported per request, 128 by default. Shorter timeouts show spans sooner, and
+// larger batches make fewer requests under load.
+func WithExportBatching(timeout time.Duration, maxBatchSize int) Option {
+	return option(func(conf *config) {
+		conf.exportBatchTimeout = timeout
+		conf.exportBatchSize = maxBatchSize
+	})
+}
+
+// WithOpenCensusBridge routes spans created by libraries still instrumented with OpenCensus
+// (such as some Google Cloud clients) through the Scout tracer provider.
+func WithOpenCensusBridge() Option {
+	return option(func(conf *config) {
+		conf.openCensusBridge = true
+	})
+}
+
+// WithTracerProvider attaches the Scout exporters to a tracer provider already configured by the application,
+// rather than creating a new provider and replacing the global one.
+// The provider's own sampler and resource are used, so sampling and resource options are ignored.
+// The Scout propagator is still registered globally, unless WithPrivateTracerProvider is set.
+func WithTracerProvider(tp *sdktrace.TracerProvider) Option {
+	return option(func(conf *config) {
+		conf.tracerProvider = tp
+	})
+}
+
+// WithPrivateTracerProvider creates the Scout tracer provider without registering it, or the Scout
+// propagator, globally.
+func WithPrivateTracerProvider() Option {
+	return option(func(conf *config) {
+		conf.privateTracerProvider = true
+	})
+}
+
+// WithSpanProcessor adds a span processor to the pipeline, ahead of the Scout exporters.
+// It may be used multiple times; processors are invoked in the order they were added.
+func WithSpanProcessor(sp sdktrace.SpanProcessor) Option {
+	return option(func(conf *config) {
+		conf.spanProcessors = append(conf.spanProcessors, sp)
+	})
+}
+
+// WithSpanLimits sets the limits on the attributes, events and links recorded per span and on the
+// length of attribute values. The limits are used as-is, so start from sdktrace.NewSpanLimits:
+//
+//	limits := sdktrace.NewSpanLimits()
+//	limits.EventCountLimit = 1024
+//	limits.AttributeValueLengthLimit = 256
+//	scout.Init(scout.WithSpanLimits(limits))
+//
+// The limits do not apply to a provider supplied with WithTracerProvider.
+func WithSpanLimits(limits sdktrace.SpanLimits) Option {
+	return option(func(conf *config) {
+		conf.spanLimits = &limits
+	})
+}
+
+// WithIDGenerator sets the generator of trace and span IDs, for example to use time-prefixed trace IDs.
+// Spans of requests carrying a Scout request ID keep the trace ID derived from it.
+// The generator does not apply to a provider supplied with WithTracerProvider.
+func WithIDGenerator(generator sdktrace.IDGenerator) Option {
+	return option(func(conf *config) {
+		conf.idGenerator = generator
+	})
+}
+
+// WithExportTimeout bounds the time spent exporting each batch of spans, including retries.
+// Without it, a request to the OTLP endpoint times out after 10 seconds (or OTEL_EXPORTER_OTLP_TIMEOUT)
+// and failed requests are retried for up to a minute, which can delay Stop.
+func WithExportTimeout(timeout time.Duration) Option {
+	return option(func(conf *config) {
+		conf.exportTimeout = timeout
+	})
+}
+
+// WithBeforeSendSpan sets a hook called with every span just before export, after scrubbing.
+// Returning false drops the span. With several exporters configured, the hook runs once per exporter.
+func WithBeforeSendSpan(fn func(span sdktrace.ReadOnlySpan) bool) Option {
+	return option(func(conf *config) {
+		conf.beforeSendSpan = fn
+	})
+}
+
 func WithServiceName(serviceName string) Option {
 	return option(func(conf *config) {
 		attr := semconv.ServiceNameKey.String(serviceName)
@@ -101,6 +262,7 @@ const (
 	Scout           contextKey = "scout"
 	RequestID                  = Scout + "RequestID"
 	SessionSecureID            = Scout + "SessionSecureID"
+	ProjectID                  = Scout + "ProjectID"
 )
 
 func ScopedKey(key string, separator *string) string {
@@ -115,9 +277,11 @@ var (
 	ContextKeys = struct {
 		RequestID       contextKey
 		SessionSecureID contextKey
+		ProjectID       contextKey
 	}{
 		RequestID:       RequestID,
 		SessionSecureID: SessionSecureID,
+		ProjectID:       ProjectID,
 	}
 )
 
@@ -133,11 +297,32 @@ const (
 )
 
 var (
-	state      appState
-	stateMutex sync.RWMutex
+	// state holds the current appState and is read lock-free on hot paths.
+	// stateMutex serializes the start and shutdown transitions.
+	state      atomic.Int32
+	stateMutex sync.Mutex
 	otlp       *OTLP
 )
 
+func loadState() appState {
+	return appState(state.Load())
+}
+
+func storeState(s appState) {
+	state.Store(int32(s))
+}
+
+func (s appState) String() string {
+	switch s {
+	case started:
+		return "started"
+	case stopped:
+		return "stopped"
+	default:
+		return "idle"
+	}
+}
+
 const (
 	consumeErrorWorkerStopped = "scout worker stopped"
 )
@@ -149,7 +334,7 @@ type Logger interface {
 }
 
 var logger struct {
-	Logger
+	LeveledLogger
 }
 
 // noop default logger
@@ -157,15 +342,18 @@ type deadLog struct{}
 
 func (d deadLog) Error(_ ...interface{})            {}
 func (d deadLog) Errorf(_ string, _ ...interface{}) {}
+func (d deadLog) Warnf(_ string, _ ...interface{})  {}
+func (d deadLog) Infof(_ string, _ ...interface{})  {}
+func (d deadLog) Debugf(_ string, _ ...interface{}) {}
 
 func init() {
 	interruptChan = make(chan bool, 1)
 	signalChan = make(chan os.Signal, 1)
-	conf = &config{}
+	activeConfig.Store(&config{otelEndpoint: OTLPDefaultEndpoint})
 
 	signal.Notify(signalChan, syscall.SIGABRT, syscall.SIGTERM, syscall.SIGINT)
-	SetOtelEndpoint(OTLPDefaultEndpoint)
-	SetDebugMode(deadLog{})
+	SetDebugMode(defaultLogger())
+	loadDisabledIntegrations()
 }
 
 // Initialise telemetry collector
@@ -176,34 +364,69 @@ func Init(opts ...Option) {
 // StartWithContext is used to start Scout's telemetry collection service, but allows the user to pass in their own context.Context.
 // This allows the user kill the Scout worker by invoking context.CancelFunc.
 func StartWithContext(ctx context.Context, opts ...Option) {
-	for _, opt := range opts {
-		opt.apply(conf)
+	if !start(opts) {
+		return
 	}
+	go func() {
+		select {
+		case <-interruptChan:
+		case <-signalChan:
+			shutdown(context.Background())
+		case <-ctx.Done():
+			shutdown(context.Background())
+		}
+	}()
+}
+
+// start applies opts and starts Scout, reporting whether it was started rather than already running.
+// Stopping Scout is left to the caller.
+func start(opts []Option) bool {
 	stateMutex.Lock()
 	defer stateMutex.Unlock()
-	if state == started {
-		return
+	if loadState() == started {
+		// the exporter and processors are already built, so only the options taking effect at runtime apply
+		if err := Reconfigure(opts...); err != nil {
+			logger.Errorf("scout is already running, restart it to apply the options: %s", err)
+		}
+		return false
+	}
+	updateConfig(func(next *config) {
+		// options take precedence over the preset from the environment
+		if preset := os.Getenv(envPreset); preset != "" {
+			WithPreset(Preset(preset)).apply(next)
+		}
+		for _, opt := range opts {
+			opt.apply(next)
+		}
+	})
+	conf := loadConfig()
+	// drop an interrupt left by a Stop no goroutine was watching for
+	select {
+	case <-interruptChan:
+	default:
 	}
 	var err error
 	otlp, err = StartOTLP()
 	if err != nil {
 		logger.Errorf("failed to start opentelemetry exporter: %s", err)
+	} else {
+		logger.Infof("started exporting telemetry for project %q", conf.projectID)
 	}
-	state = started
-	go func() {
-		for {
-			select {
-			case <-interruptChan:
-				return
-			case <-signalChan:
-				shutdown()
-				return
-			case <-ctx.Done():
-				shutdown()
-				return
-			}
-		}
-	}()
+	storeState(started)
+	startWorkers()
+	startHeartbeat()
+	startProfiler()
+	startContentionProfiler()
+	startExecutionTracer(otlp)
+	startRuntimeMetrics()
+	startCgroupMetrics()
+	startApdex(otlp)
+	startAlerts(otlp)
+	startMetricBatching()
+	startLogBatching()
+	startLogExport(otlp)
+	startHeapWatchdog()
+	return true
 }
 
 // Start readies Scout to start collecting telemetry.
@@ -211,35 +434,74 @@ func Start(opts ...Option) {
 	StartWithContext(context.Background(), opts...)
 }
 
-// Flush buffers and stop collecting telemetry
-func Stop() {
-	interruptChan <- true
-	shutdown()
+// Flush buffers and stop collecting telemetry, returning a summary of the flush.
+// The summary is empty if Scout was not running.
+func Stop() ShutdownSummary {
+	interrupt()
+	return shutdown(context.Background())
+}
+
+// interrupt stops the goroutine StartWithContext watches the signals and its context with, if any.
+func interrupt() {
+	select {
+	case interruptChan <- true:
+	default:
+	}
 }
 
 func IsRunning() bool {
-	return state == started
+	return loadState() == started
+}
+
+// TracerProvider returns the provider Scout exports spans from, so applications and instrumentation
+// libraries can create their own tracers against it. This is the provider passed to WithTracerProvider
+// or, by default, the *sdktrace.TracerProvider created on start. Before Scout starts, it returns the
+// global provider.
+func TracerProvider() trace.TracerProvider {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	if otlp == nil || loadState() != started {
+		return otel.GetTracerProvider()
+	}
+	return otlp.tracerProvider
 }
 
 // SetOtelEndpoint allows you to override the otlp address used for sending errors and traces.
 // Use the root http url. Eg: https://otel.scout.us:4318
+// It takes precedence over the OTEL_EXPORTER_OTLP_ENDPOINT environment variable, and is only read on start.
 func SetOtelEndpoint(newotelEndpoint string) {
-	conf.otelEndpoint = newotelEndpoint
+	if IsRunning() {
+		logger.Warnf("the otlp endpoint is only read on start, the new endpoint takes effect once Scout restarts")
+	}
+	updateConfig(func(next *config) {
+		next.otelEndpoint = newotelEndpoint
+		next.otelEndpointSet = true
+	})
 }
 
+// SetDebugMode sets the logger receiving the SDK's diagnostics. Loggers implementing LeveledLogger,
+// such as a *logrus.Logger, also receive warnings, informational and debug messages.
 func SetDebugMode(l Logger) {
-	logger.Logger = l
+	leveled, ok := l.(LeveledLogger)
+	if !ok {
+		leveled = errorLogger{Logger: l}
+	}
+	logger.LeveledLogger = leveled
 }
 
 func SetProjectID(id string) {
-	conf.projectID = id
+	updateConfig(func(next *config) {
+		next.projectID = id
+	})
 }
 
 func GetProjectID() string {
+	conf := loadConfig()
 	return conf.projectID
 }
 
 func GetMetricSamplingRate() float64 {
+	conf := loadConfig()
 	return conf.metricSamplingRate
 }
 
@@ -251,46 +513,147 @@ func InterceptRequest(r *http.Request) context.Context {
 // InterceptRequestWithContext captures the and request ID
 // for a particular request from the request headers, adding the values to the provided context.
 func InterceptRequestWithContext(ctx context.Context, r *http.Request) context.Context {
-	header := r.Header.Get("X-Scout-Request")
-	ids := strings.Split(header, "/")
-	if len(ids) < 2 {
-		return ctx
+	return InterceptRequestHeader(ctx, r.Header.Get(RequestTracerHeader))
+}
+
+// InterceptRequestHeader adds the session and request IDs of an X-Scout-Request header value to ctx,
+// for integrations with frameworks not built on net/http. If the value is missing or invalid and
+// WithRequestIDGeneration is set, a new request ID without a session is generated instead.
+func InterceptRequestHeader(ctx context.Context, requestDetails string) context.Context {
+	conf := loadConfig()
+	sessionSecureID, requestID, err := ExtractIdsFromRequest(requestDetails)
+	if err != nil {
+		if !conf.generateRequestIDs {
+			return ctx
+		}
+		requestID = NewRequestID()
+		ctx = context.WithValue(ctx, generatedRequestIDKey, requestID)
 	}
-	ctx = context.WithValue(ctx, ContextKeys.SessionSecureID, ids[0])
-	ctx = context.WithValue(ctx, ContextKeys.RequestID, ids[1])
+	ctx = ContextWithSessionID(ctx, sessionSecureID)
+	ctx = ContextWithRequestID(ctx, requestID)
 	return ctx
 }
 
-func validateRequest(ctx context.Context) (sessionSecureID string, requestID string, err error) {
-	stateMutex.RLock()
-	defer stateMutex.RUnlock()
-	if state == stopped {
-		err = errors.New(consumeErrorWorkerStopped)
-		return
+// generatedRequestIDKey marks request IDs generated by Scout rather than received from the client.
+const generatedRequestIDKey = Scout + "GeneratedRequestID"
+
+// WithRequestIDGeneration generates a request ID for requests without an X-Scout-Request header, so
+// each request gets its own trace. Middleware echo generated IDs in the X-Scout-Request-Id response
+// header so client reports can be matched to backend traces.
+func WithRequestIDGeneration() Option {
+	return runtimeOption(func(conf *config) {
+		conf.generateRequestIDs = true
+	})
+}
+
+// NewRequestID returns a random request ID, which identifies the trace of the request.
+func NewRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return base64.StdEncoding.EncodeToString(id[:])
+}
+
+// GeneratedRequestID returns the request ID generated for ctx by InterceptRequest, or an empty string
+// if the request ID was received from the client or none was generated. Middleware set it as the
+// RequestIDResponseHeader of the response.
+func GeneratedRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(generatedRequestIDKey).(string)
+	return id
+}
+
+// ContextWithSessionID returns a copy of ctx carrying the Scout session secure ID.
+// Middleware and application code should use it rather than setting ContextKeys directly.
+func ContextWithSessionID(ctx context.Context, sessionSecureID string) context.Context {
+	return context.WithValue(ctx, ContextKeys.SessionSecureID, sessionSecureID)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the Scout request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ContextKeys.RequestID, requestID)
+}
+
+// CopyRequestIDs returns a copy of dst carrying the session, request and generated request IDs of src,
+// for frameworks that keep request values apart from the context handed to handlers.
+func CopyRequestIDs(dst, src context.Context) context.Context {
+	if id := GetSessionID(src); id != "" {
+		dst = ContextWithSessionID(dst, id)
+	}
+	if id := GetRequestID(src); id != "" {
+		dst = ContextWithRequestID(dst, id)
+	}
+	if id := GeneratedRequestID(src); id != "" {
+		dst = context.WithValue(dst, generatedRequestIDKey, id)
+	}
+	return dst
+}
+
+// WithProjectIDOverride returns a copy of ctx whose telemetry is sent to the given Scout project
+// rather than the project configured with WithProjectID, for example to route each tenant of
+// a multi-tenant service to its own project.
+func WithProjectIDOverride(ctx context.Context, projectID string) context.Context {
+	return context.WithValue(ctx, ContextKeys.ProjectID, projectID)
+}
+
+// ProjectIDFromContext returns the project ID set on ctx by WithProjectIDOverride,
+// or the configured project ID if there is no override.
+func ProjectIDFromContext(ctx context.Context) string {
+	conf := loadConfig()
+	if v, ok := ctx.Value(ContextKeys.ProjectID).(string); ok && v != "" {
+		return v
 	}
-	if v := ctx.Value(string(ContextKeys.SessionSecureID)); v != nil {
-		sessionSecureID = v.(string)
+	return conf.projectID
+}
+
+// GetSessionID returns the Scout session secure ID carried by ctx, or an empty string if there is none.
+func GetSessionID(ctx context.Context) string {
+	if v, ok := ctx.Value(ContextKeys.SessionSecureID).(string); ok {
+		return v
 	}
-	if v := ctx.Value(ContextKeys.SessionSecureID); v != nil {
-		sessionSecureID = v.(string)
+	return legacyContextValue(ctx, ContextKeys.SessionSecureID)
+}
+
+// GetRequestID returns the Scout request ID carried by ctx, or an empty string if there is none.
+func GetRequestID(ctx context.Context) string {
+	if v, ok := ctx.Value(ContextKeys.RequestID).(string); ok {
+		return v
 	}
-	if v := ctx.Value(string(ContextKeys.RequestID)); v != nil {
-		requestID = v.(string)
+	return legacyContextValue(ctx, ContextKeys.RequestID)
+}
+
+// legacyContextValue reads values stored under the plain string form of a context key,
+// as done by contexts such as gin.Context that only support string keys.
+func legacyContextValue(ctx context.Context, key contextKey) string {
+	if v, ok := ctx.Value(string(key)).(string); ok {
+		return v
 	}
-	if v := ctx.Value(ContextKeys.RequestID); v != nil {
-		requestID = v.(string)
+	return ""
+}
+
+func validateRequest(ctx context.Context) (sessionSecureID string, requestID string, err error) {
+	if loadState() == stopped {
+		err = errors.New(consumeErrorWorkerStopped)
+		return
 	}
-	return
+	return GetSessionID(ctx), GetRequestID(ctx), nil
 }
 
-func shutdown() {
+func shutdown(ctx context.Context) ShutdownSummary {
 	stateMutex.Lock()
 	defer stateMutex.Unlock()
 	if !IsRunning() {
-		return
+		return ShutdownSummary{}
 	}
+	start := time.Now()
+	totals := exportTotals()
+	// stop the background tasks first so the telemetry they record is flushed
+	stopWorkers()
+	var summary ShutdownSummary
 	if otlp != nil {
-		otlp.shutdown()
+		summary = flushTelemetry(ctx, otlp, start, totals)
+		recordShutdownSummary(summary)
+		otlp.shutdown(ctx)
 	}
-	state = stopped
+	storeState(stopped)
+	logger.Infof("stopped exporting telemetry")
+	return summary
 }
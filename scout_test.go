@@ -2,10 +2,19 @@ package scout
 
 import (
 	"context"
+	"net/http"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TestConsumeError tests every case for RecordMetric
@@ -90,3 +99,246 @@ func TestExtractIdsFromRequest(t *testing.T) {
 
 	}
 }
+
+func TestIsRunningConcurrentWithLifecycle(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			IsRunning()
+			_, _, _ = validateRequest(context.Background())
+		}
+	}()
+	Init()
+	if !IsRunning() {
+		t.Fatalf("[IsRunning] expected scout to be running after Init")
+	}
+	Stop()
+	<-done
+	if IsRunning() {
+		t.Fatalf("[IsRunning] expected scout to be stopped after Stop")
+	}
+}
+
+func TestContextIDs(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(RequestTracerHeader, "session/request")
+	legacy := context.WithValue(context.Background(), string(ContextKeys.SessionSecureID), "legacy-session")
+	legacy = context.WithValue(legacy, string(ContextKeys.RequestID), "legacy-request")
+
+	tests := []struct {
+		ctx             context.Context
+		sessionSecureID string
+		requestID       string
+	}{
+		{context.Background(), "", ""},
+		{InterceptRequest(r), "session", "request"},
+		{ContextWithRequestID(ContextWithSessionID(context.Background(), "a"), "b"), "a", "b"},
+		{legacy, "legacy-session", "legacy-request"},
+		{ContextWithSessionID(legacy, "typed-session"), "typed-session", "legacy-request"},
+	}
+
+	for _, tt := range tests {
+		if sessionSecureID := GetSessionID(tt.ctx); sessionSecureID != tt.sessionSecureID {
+			t.Fatalf("[GetSessionID] expected %s, got %s", tt.sessionSecureID, sessionSecureID)
+		}
+		if requestID := GetRequestID(tt.ctx); requestID != tt.requestID {
+			t.Fatalf("[GetRequestID] expected %s, got %s", tt.requestID, requestID)
+		}
+	}
+}
+
+func TestProjectIDOverride(t *testing.T) {
+	recorder := recordSpans(t)
+	prev := GetProjectID()
+	SetProjectID("default")
+	t.Cleanup(func() { SetProjectID(prev) })
+
+	span, _ := StartTrace(context.Background(), "default")
+	EndTrace(span)
+	span, _ = StartTrace(WithProjectIDOverride(context.Background(), "tenant"), "tenant")
+	EndTrace(span)
+
+	spans := recorder.Ended()
+	for i, expected := range []string{"default", "tenant"} {
+		for _, kv := range spans[i].Attributes() {
+			if kv.Key == ProjectIDAttribute && kv.Value.AsString() != expected {
+				t.Fatalf("[WithProjectIDOverride] expected project %s, got %s", expected, kv.Value.AsString())
+			}
+		}
+	}
+}
+
+func TestTracerProvider(t *testing.T) {
+	Init(WithPrivateTracerProvider())
+	if _, ok := TracerProvider().(*sdktrace.TracerProvider); !ok || TracerProvider() == otel.GetTracerProvider() {
+		t.Fatalf("[TracerProvider] expected the private provider created on start, got %T", TracerProvider())
+	}
+	Stop()
+	if TracerProvider() != otel.GetTracerProvider() {
+		t.Fatalf("[TracerProvider] expected the global provider once stopped")
+	}
+}
+
+func TestRecordEvent(t *testing.T) {
+	recorder := recordSpans(t)
+	prev := loadState()
+	storeState(idle)
+	defer storeState(prev)
+	ctx := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "request")
+
+	RecordEvent(ctx, "user.invited", attribute.String("user.role", "admin"))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("[RecordEvent] expected 1 span, got %d", len(spans))
+	}
+	if !slices.Contains(spans[0].Attributes(), attribute.String(SessionIDAttribute, "session")) {
+		t.Fatalf("[RecordEvent] expected the event to be attributed to the session, got %v", spans[0].Attributes())
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != BusinessEvent {
+		t.Fatalf("[RecordEvent] expected one %s event, got %+v", BusinessEvent, events)
+	}
+	want := []attribute.KeyValue{attribute.String(BusinessEventName, "user.invited"), attribute.String("user.role", "admin")}
+	if !slices.Equal(events[0].Attributes, want) {
+		t.Fatalf("[RecordEvent] expected attributes %v, got %v", want, events[0].Attributes)
+	}
+}
+
+func TestRecordWithTimestamp(t *testing.T) {
+	recorder := recordSpans(t)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	RecordMetricWithTimestamp(context.Background(), "queue.depth", 12, at)
+	RecordErrorWithTimestamp(context.Background(), errors.New("replayed"), at)
+	RecordErrorWithTimestamp(context.Background(), errors.WithStack(errors.New("replayed with stack")), at)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("[RecordWithTimestamp] expected 3 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if !span.StartTime().Equal(at) || !span.EndTime().Equal(at) {
+			t.Fatalf("[RecordWithTimestamp] expected span %s at %s, got %s to %s", span.Name(), at, span.StartTime(), span.EndTime())
+		}
+		if events := span.Events(); len(events) != 1 || !events[0].Time.Equal(at) {
+			t.Fatalf("[RecordWithTimestamp] expected one event at %s, got %+v", at, events)
+		}
+	}
+}
+
+func TestRequestIDGeneration(t *testing.T) {
+	useConfig(t, &config{})
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if ctx := InterceptRequest(r); GetRequestID(ctx) != "" {
+		t.Fatalf("[InterceptRequest] expected no request ID without WithRequestIDGeneration")
+	}
+
+	updateConfig(WithRequestIDGeneration().apply)
+	ctx := InterceptRequest(r)
+	if id := GetRequestID(ctx); len(id) != 24 || GeneratedRequestID(ctx) != id || GetSessionID(ctx) != "" {
+		t.Fatalf("[InterceptRequest] expected a generated sessionless request ID, got %q", id)
+	}
+
+	r.Header.Set(RequestTracerHeader, "session/request")
+	if ctx := InterceptRequest(r); GetRequestID(ctx) != "request" || GeneratedRequestID(ctx) != "" {
+		t.Fatalf("[InterceptRequest] expected the request ID from the header")
+	}
+}
+
+func TestCopyRequestIDs(t *testing.T) {
+	src := InterceptRequestHeader(context.Background(), "session/request")
+	dst := CopyRequestIDs(context.Background(), src)
+	if GetSessionID(dst) != "session" || GetRequestID(dst) != "request" {
+		t.Fatalf("[CopyRequestIDs] expected session and request IDs, got %q %q", GetSessionID(dst), GetRequestID(dst))
+	}
+	if ctx := CopyRequestIDs(context.Background(), context.Background()); ctx.Value(ContextKeys.RequestID) != nil {
+		t.Fatalf("[CopyRequestIDs] expected no request ID to be set")
+	}
+}
+
+func TestForceSampleAttribute(t *testing.T) {
+	s := sampler{traceIDUpperBounds: map[trace.SpanKind]uint64{trace.SpanKindUnspecified: 0}}
+	params := sdktrace.SamplingParameters{TraceID: trace.TraceID{15: 1}}
+	if s.ShouldSample(params).Decision != sdktrace.Drop {
+		t.Fatalf("[ShouldSample] expected the span to be dropped")
+	}
+	params.Attributes = []attribute.KeyValue{attribute.Bool(ForceSampleAttribute, true)}
+	if s.ShouldSample(params).Decision != sdktrace.RecordAndSample {
+		t.Fatalf("[ShouldSample] expected the force sampled span to be sampled")
+	}
+}
+
+func TestForceSample(t *testing.T) {
+	prev := loadTracer()
+	s := sampler{traceIDUpperBounds: map[trace.SpanKind]uint64{trace.SpanKindUnspecified: 0}}
+	storeTracer(newTracer(sdktrace.NewTracerProvider(sdktrace.WithSampler(s))))
+	defer storeTracer(prev)
+
+	if span, _ := StartTrace(context.Background(), "dropped"); span.SpanContext().IsSampled() {
+		t.Fatalf("[ForceSample] expected spans to be dropped by the sampler")
+	}
+	ctx := ForceSample(context.Background())
+	parent, ctx := StartTrace(ctx, "payment.failed")
+	child, _ := StartTrace(ctx, "refund")
+	if !parent.SpanContext().IsSampled() || !child.SpanContext().IsSampled() {
+		t.Fatalf("[ForceSample] expected the forced trace to be sampled")
+	}
+
+	header := http.Header{}
+	InjectHTTPHeaders(ctx, header)
+	extracted := Extract(context.Background(), propagation.HeaderCarrier(header))
+	if downstream, _ := StartTrace(extracted, "downstream"); !downstream.SpanContext().IsSampled() {
+		t.Fatalf("[ForceSample] expected the forced trace to stay sampled downstream, got tracestate %q", header.Get("Tracestate"))
+	}
+}
+
+func TestSampledOutSpans(t *testing.T) {
+	prev := loadTracer()
+	recorder := tracetest.NewSpanRecorder()
+	s := sampler{traceIDUpperBounds: map[trace.SpanKind]uint64{trace.SpanKindUnspecified: 0}}
+	storeTracer(newTracer(sdktrace.NewTracerProvider(sdktrace.WithSampler(s), sdktrace.WithSpanProcessor(recorder))))
+	defer storeTracer(prev)
+
+	span, ctx := StartTrace(context.Background(), "dropped", attribute.String("tenant", "acme"))
+	if span.IsRecording() {
+		t.Fatalf("[StartTrace] expected the span dropped by the sampler not to be recording")
+	}
+	if !trace.SpanContextFromContext(ctx).Equal(span.SpanContext()) {
+		t.Fatalf("[StartTrace] expected the dropped span to be the active span in the returned context")
+	}
+	EndTrace(span)
+	RecordMetric(context.Background(), "latency", 1.5)
+	RecordErrors(context.Background(), []error{errors.New("failed")})
+	dropped, _ := StartTraceWithoutResourceAttributes(context.Background(), "dropped", nil)
+	EndTrace(dropped)
+	if started := recorder.Started(); len(started) != 0 {
+		t.Fatalf("[StartTrace] expected no span to be recorded, got %d", len(started))
+	}
+
+	RecordEvent(context.Background(), "user.invited")
+	ended := recorder.Ended()
+	if len(ended) != 1 || !slices.Contains(ended[0].Attributes(), attribute.String(ProjectIDAttribute, "")) {
+		t.Fatalf("[StartTrace] expected the force sampled span to be recorded with its attributes, got %v", ended)
+	}
+}
+
+func TestStartTraceWhileStarting(t *testing.T) {
+	prev := loadTracer()
+	defer storeTracer(prev)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			storeTracer(newTracer(sdktrace.NewTracerProvider()))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		span, _ := StartTrace(context.Background(), "concurrent")
+		EndTrace(span)
+	}
+	<-done
+}
@@ -0,0 +1,146 @@
+// Package scoutcache records the hits, misses and hit ratio of caches as metrics, so a drop in cache
+// efficiency shows up in Scout rather than as a slow rise in latency.
+package scoutcache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/scout-inc/scout-go/metric"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RatioInterval is the default interval at which the gets and sets of a cache and its hit ratio are
+// reported, see WithInterval.
+const RatioInterval = 10 * time.Second
+
+var (
+	nameKey   = attribute.Key("cache.name")
+	resultKey = attribute.Key("cache.result")
+)
+
+// Cache counts the gets on a cache, and reports them once per interval as cache.get counters tagged with
+// the cache name and a cache.result of hit or miss, along with the hit ratio of the interval as a
+// cache.hit_ratio gauge. Counting in memory keeps the instrumentation cheap for caches on hot paths.
+type Cache[K, V any] struct {
+	name     string
+	get      func(key K) (V, bool)
+	interval time.Duration
+	now      func() time.Time
+
+	hits, misses, sets atomic.Int64
+	// windowStart is when the counts of the current interval started, in nanoseconds
+	windowStart atomic.Int64
+}
+
+// Option configures a Cache.
+type Option func(*options)
+
+type options struct {
+	interval time.Duration
+}
+
+// WithInterval reports the counts and the hit ratio of the cache every interval rather than every
+// RatioInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.interval = interval
+	}
+}
+
+// Wrap records the gets of the cache called name through get, which returns the cached value of key and
+// whether it was found. It works with any cache, for example:
+//
+//	cache := scoutcache.Wrap("users", ristrettoCache.Get)
+//	user, ok := cache.Get(ctx, id)
+//
+//	blobs := scoutcache.Wrap("blobs", func(key string) ([]byte, bool) {
+//		b, err := bigCache.Get(key)
+//		return b, err == nil
+//	})
+//
+// The counts are reported on the first get or set after the interval ends, so the counts of a cache
+// that is no longer used are reported with Report.
+func Wrap[K, V any](name string, get func(key K) (V, bool), opts ...Option) *Cache[K, V] {
+	o := options{interval: RatioInterval}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Cache[K, V]{name: name, get: get, interval: o.interval, now: time.Now}
+}
+
+// Get returns the cached value of key and whether it was found, counting a hit or a miss.
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	value, ok := c.get(key)
+	if !scout.IntegrationEnabled(scout.IntegrationCache) {
+		return value, ok
+	}
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	c.reportInterval()
+	return value, ok
+}
+
+// RecordSet counts a value set in the cache, for comparing the writes to a cache with its misses.
+func (c *Cache[K, V]) RecordSet(ctx context.Context) {
+	if !scout.IntegrationEnabled(scout.IntegrationCache) {
+		return
+	}
+	c.sets.Add(1)
+	c.reportInterval()
+}
+
+// HitRatio returns the share of the gets of the current interval that were hits.
+func (c *Cache[K, V]) HitRatio() float64 {
+	return hitRatio(c.hits.Load(), c.misses.Load())
+}
+
+// Report reports the counts of the current interval and starts a new one.
+func (c *Cache[K, V]) Report(ctx context.Context) {
+	c.windowStart.Store(c.now().UnixNano())
+	c.report(ctx)
+}
+
+// reportInterval reports the counts once the current interval has ended. They are reported outside of the
+// request whose get or set ended the interval, as they are not part of it.
+func (c *Cache[K, V]) reportInterval() {
+	now := c.now().UnixNano()
+	start := c.windowStart.Load()
+	if start == 0 {
+		c.windowStart.CompareAndSwap(0, now)
+		return
+	}
+	if now-start < int64(c.interval) || !c.windowStart.CompareAndSwap(start, now) {
+		return
+	}
+	c.report(context.Background())
+}
+
+func (c *Cache[K, V]) report(ctx context.Context) {
+	hits, misses, sets := c.hits.Swap(0), c.misses.Swap(0), c.sets.Swap(0)
+	name := nameKey.String(c.name)
+	if hits > 0 {
+		metric.Count(ctx, "cache.get", float64(hits), []attribute.KeyValue{name, resultKey.String("hit")}, 1)
+	}
+	if misses > 0 {
+		metric.Count(ctx, "cache.get", float64(misses), []attribute.KeyValue{name, resultKey.String("miss")}, 1)
+	}
+	if sets > 0 {
+		metric.Count(ctx, "cache.set", float64(sets), []attribute.KeyValue{name}, 1)
+	}
+	if hits+misses > 0 {
+		metric.Gauge(ctx, "cache.hit_ratio", hitRatio(hits, misses), []attribute.KeyValue{name})
+	}
+}
+
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
@@ -0,0 +1,99 @@
+package scoutcache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	recorder = tracetest.NewSpanRecorder()
+	// seen is the number of recorded spans returned by reported
+	seen int
+)
+
+func TestMain(m *testing.M) {
+	// scout traces with the global provider until it is started
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	if err := scout.Reconfigure(scout.WithMetricSamplingRate(1)); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// reported returns the values of the metrics recorded since the last call, by metric name and
+// cache.result tag.
+func reported() map[string]float64 {
+	metrics := map[string]float64{}
+	spans := recorder.Ended()
+	for _, span := range spans[seen:] {
+		var name string
+		var value float64
+		for _, kv := range span.Events()[0].Attributes {
+			switch kv.Key {
+			case scout.MetricEventName:
+				name = kv.Value.AsString()
+			case scout.MetricEventValue:
+				value = kv.Value.AsFloat64()
+			}
+		}
+		for _, kv := range span.Attributes() {
+			if kv.Key == resultKey {
+				name += "." + kv.Value.AsString()
+			}
+		}
+		metrics[name] = value
+	}
+	seen = len(spans)
+	return metrics
+}
+
+func TestCache(t *testing.T) {
+	values := map[string]int{"a": 1}
+	cache := Wrap("values", func(key string) (int, bool) {
+		v, ok := values[key]
+		return v, ok
+	})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	assert.Zero(t, cache.HitRatio())
+	reported()
+
+	value, ok := cache.Get(context.Background(), "a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	_, ok = cache.Get(context.Background(), "a")
+	assert.True(t, ok)
+	_, ok = cache.Get(context.Background(), "b")
+	assert.False(t, ok)
+	_, ok = cache.Get(context.Background(), "c")
+	assert.False(t, ok)
+	cache.RecordSet(context.Background())
+	assert.Equal(t, 0.5, cache.HitRatio())
+	assert.Empty(t, reported(), "the counts are reported once the interval ends")
+
+	now = now.Add(RatioInterval)
+	cache.Get(context.Background(), "a")
+	assert.Equal(t, map[string]float64{
+		"cache.get.hit":   3,
+		"cache.get.miss":  2,
+		"cache.set":       1,
+		"cache.hit_ratio": 0.6,
+	}, reported())
+	assert.Zero(t, cache.HitRatio(), "the hit ratio is of the current interval")
+
+	cache.Get(context.Background(), "b")
+	assert.Zero(t, cache.HitRatio())
+	cache.Report(context.Background())
+	assert.Equal(t, map[string]float64{
+		"cache.get.miss":  1,
+		"cache.hit_ratio": 0,
+	}, reported())
+}
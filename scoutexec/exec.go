@@ -0,0 +1,155 @@
+// Package scoutexec traces commands run with os/exec.
+package scoutexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StderrTailSize is the number of trailing bytes of stderr recorded on the span of a command.
+const StderrTailSize = 4 << 10
+
+var (
+	exitCodeKey = attribute.Key("process.exit_code")
+	stderrKey   = attribute.Key("process.stderr")
+)
+
+// Cmd is an exec.Cmd traced with a span from Start to Wait, capturing the program name, exit code and the
+// tail of stderr. The span is not started for commands run without Run, Start, Output or CombinedOutput.
+type Cmd struct {
+	*exec.Cmd
+
+	ctx    context.Context
+	span   trace.Span
+	stderr *tailWriter
+}
+
+// CommandContext returns a traced exec.CommandContext. The command arguments are not recorded,
+// as they may hold credentials.
+func CommandContext(ctx context.Context, name string, arg ...string) *Cmd {
+	return &Cmd{Cmd: exec.CommandContext(ctx, name, arg...), ctx: ctx}
+}
+
+// Start starts the command and its span.
+func (c *Cmd) Start() error {
+	c.stderr = &tailWriter{limit: StderrTailSize}
+	// a writer shared by stdout and stderr is kept shared, so the command writes both through one pipe
+	if c.Stdout != nil && c.Stdout == c.Stderr {
+		c.Stdout = io.MultiWriter(c.Stdout, c.stderr)
+		c.Stderr = c.Stdout
+	} else if c.Stderr != nil {
+		c.Stderr = io.MultiWriter(c.Stderr, c.stderr)
+	} else {
+		c.Stderr = c.stderr
+	}
+
+	if scout.IntegrationEnabled(scout.IntegrationExec) {
+		c.span, _ = scout.StartTrace(c.ctx, scout.ScopedKey("exec", nil),
+			semconv.ProcessExecutableName(filepath.Base(c.Path)),
+		)
+	}
+	if err := c.Cmd.Start(); err != nil {
+		c.end(err)
+		return err
+	}
+	return nil
+}
+
+// Wait waits for the command to exit and ends its span.
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	c.end(err)
+	return err
+}
+
+// Run starts the command and waits for it to exit.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Output runs the command and returns its stdout. As with exec.Cmd.Output, the stderr of a failed
+// command is returned in the exec.ExitError if Stderr was not set, limited to its tail.
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	captureStderr := c.Stderr == nil
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = c.stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its combined stdout and stderr.
+// The tail recorded on the span then holds stdout as well.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	if c.Stderr != nil {
+		return nil, errors.New("exec: Stderr already set")
+	}
+	var b bytes.Buffer
+	c.Stdout = &b
+	c.Stderr = &b
+	err := c.Run()
+	return b.Bytes(), err
+}
+
+func (c *Cmd) end(err error) {
+	if c.span == nil {
+		return
+	}
+	defer scout.EndTrace(c.span)
+	if c.ProcessState != nil {
+		c.span.SetAttributes(exitCodeKey.Int(c.ProcessState.ExitCode()))
+	}
+	if tail := c.stderr.Bytes(); len(tail) > 0 {
+		c.span.SetAttributes(stderrKey.String(string(tail)))
+	}
+	if err != nil {
+		scout.RecordSpanError(c.span, err)
+		scout.SetSpanErrorStatus(c.span, err)
+	}
+}
+
+// tailWriter keeps the last limit bytes written to it.
+type tailWriter struct {
+	limit int
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > w.limit {
+		w.buf = append(w.buf[:0], w.buf[len(w.buf)-w.limit:]...)
+	}
+	return len(p), nil
+}
+
+func (w *tailWriter) Bytes() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]byte(nil), w.buf...)
+}
@@ -0,0 +1,43 @@
+package scoutexec
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput(t *testing.T) {
+	out, err := CommandContext(context.Background(), "sh", "-c", "echo out; echo failed >&2; exit 3").Output()
+	assert.Equal(t, "out\n", string(out))
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode())
+	assert.Equal(t, "failed\n", string(exitErr.Stderr))
+}
+
+func TestCombinedOutput(t *testing.T) {
+	out, err := CommandContext(context.Background(), "sh", "-c", "echo out; echo err >&2").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "out\nerr\n", string(out))
+}
+
+func TestTailWriter(t *testing.T) {
+	w := &tailWriter{limit: 4}
+	_, _ = w.Write([]byte("abc"))
+	_, _ = w.Write([]byte(strings.Repeat("x", 2) + "yz"))
+	assert.Equal(t, "xxyz", string(w.Bytes()))
+}
+
+func TestDisabled(t *testing.T) {
+	scout.DisableIntegration(scout.IntegrationExec)
+	defer scout.EnableIntegration(scout.IntegrationExec)
+	out, err := CommandContext(context.Background(), "sh", "-c", "echo out").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "out\n", string(out))
+}
@@ -0,0 +1,114 @@
+// Package scoutmigrate traces the migration steps run by golang-migrate.
+package scoutmigrate
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	versionKey       = attribute.Key("migration.version")
+	targetVersionKey = attribute.Key("migration.target_version")
+	directionKey     = attribute.Key("migration.direction")
+)
+
+// Driver is a database.Driver tracing each migration step with a span holding the version and direction
+// of the migration, from marking the database dirty until the step is applied or fails.
+type Driver struct {
+	database.Driver
+	ctx context.Context
+
+	mu   sync.Mutex
+	span trace.Span
+}
+
+// WrapDriver traces the migrations run on drv, as children of the span in ctx. For example:
+//
+//	drv, err := postgres.WithInstance(db, &postgres.Config{})
+//	m, err := migrate.NewWithDatabaseInstance("file://migrations", "postgres", scoutmigrate.WrapDriver(ctx, drv))
+//	err = m.Up()
+func WrapDriver(ctx context.Context, drv database.Driver) *Driver {
+	return &Driver{Driver: drv, ctx: ctx}
+}
+
+// Open opens a traced connection to the database at url.
+func (d *Driver) Open(url string) (database.Driver, error) {
+	drv, err := d.Driver.Open(url)
+	if err != nil {
+		return nil, err
+	}
+	return WrapDriver(d.ctx, drv), nil
+}
+
+// Run runs a migration, ending its span if it fails.
+func (d *Driver) Run(migration io.Reader) error {
+	err := d.Driver.Run(migration)
+	if err != nil {
+		d.end(err)
+	}
+	return err
+}
+
+// SetVersion starts the span of a migration step when the database is marked dirty,
+// and ends it once the step is applied and the database is marked clean.
+func (d *Driver) SetVersion(version int, dirty bool) error {
+	if dirty {
+		d.start(version)
+	}
+	err := d.Driver.SetVersion(version, dirty)
+	if err != nil || !dirty {
+		d.end(err)
+	}
+	return err
+}
+
+func (d *Driver) start(target int) {
+	if !scout.IntegrationEnabled(scout.IntegrationMigrate) {
+		return
+	}
+	// the database is still at the version before the step
+	current, _, err := d.Driver.Version()
+	if err != nil {
+		current = database.NilVersion
+	}
+	direction, version := step(current, target)
+
+	span, _ := scout.StartTrace(d.ctx, scout.ScopedKey("migrate", nil),
+		versionKey.Int(version),
+		targetVersionKey.Int(target),
+		directionKey.String(direction),
+	)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.span = span
+}
+
+func (d *Driver) end(err error) {
+	d.mu.Lock()
+	span := d.span
+	d.span = nil
+	d.mu.Unlock()
+	if span == nil {
+		return
+	}
+	defer scout.EndTrace(span)
+	if err != nil {
+		scout.RecordSpanError(span, err)
+		scout.SetSpanErrorStatus(span, err)
+	}
+}
+
+// step returns the direction of the migration from the current to the target version,
+// and the version of the migration, which for down migrations is the version undone.
+func step(current, target int) (direction string, version int) {
+	if current != database.NilVersion && target < current {
+		return "down", current
+	}
+	return "up", target
+}
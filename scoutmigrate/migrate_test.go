@@ -0,0 +1,72 @@
+package scoutmigrate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		current, target int
+		direction       string
+		version         int
+	}{
+		{database.NilVersion, 1, "up", 1},
+		{1, 2, "up", 2},
+		{2, 1, "down", 2},
+		{1, database.NilVersion, "down", 1},
+	}
+	for _, tt := range tests {
+		direction, version := step(tt.current, tt.target)
+		assert.Equal(t, tt.direction, direction, "%d to %d", tt.current, tt.target)
+		assert.Equal(t, tt.version, version, "%d to %d", tt.current, tt.target)
+	}
+}
+
+// stubDriver records the migrations run on it.
+type stubDriver struct {
+	database.Driver
+	version int
+	dirty   bool
+	ran     []string
+	err     error
+}
+
+func (d *stubDriver) Run(migration io.Reader) error {
+	body, _ := io.ReadAll(migration)
+	d.ran = append(d.ran, string(body))
+	return d.err
+}
+
+func (d *stubDriver) SetVersion(version int, dirty bool) error {
+	d.version, d.dirty = version, dirty
+	return nil
+}
+
+func (d *stubDriver) Version() (int, bool, error) {
+	return d.version, d.dirty, nil
+}
+
+func TestDriver(t *testing.T) {
+	stub := &stubDriver{version: database.NilVersion}
+	drv := WrapDriver(context.Background(), stub)
+
+	require.NoError(t, drv.SetVersion(1, true))
+	require.NoError(t, drv.Run(strings.NewReader("CREATE TABLE a")))
+	require.NoError(t, drv.SetVersion(1, false))
+	assert.Nil(t, drv.span)
+
+	stub.err = errors.New("syntax error")
+	require.NoError(t, drv.SetVersion(2, true))
+	require.Error(t, drv.Run(strings.NewReader("CREATE TABLE")))
+	assert.Nil(t, drv.span)
+	assert.Equal(t, []string{"CREATE TABLE a", "CREATE TABLE"}, stub.ran)
+	assert.True(t, stub.dirty)
+}
@@ -0,0 +1,247 @@
+package scout
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// RedactedValue replaces sensitive data removed before export.
+const RedactedValue = "[REDACTED]"
+
+var (
+	creditCardPattern   = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	ssnPattern          = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	bearerTokenPattern  = regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`)
+	awsAccessKeyPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+	emailPattern        = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+)
+
+// defaultRedactedQueryParams are query parameters that commonly carry credentials or personal data.
+var defaultRedactedQueryParams = []string{
+	"access_token", "api_key", "apikey", "auth", "authorization", "client_secret", "code", "email",
+	"id_token", "key", "passwd", "password", "pwd", "refresh_token", "secret", "session", "sig", "signature", "token",
+}
+
+// defaultScrubbedAttributes hold error messages and log bodies.
+var defaultScrubbedAttributes = []attribute.Key{
+	semconv.ExceptionMessageKey,
+	semconv.ExceptionStacktraceKey,
+	LogMessageAttribute,
+}
+
+// requiredAttributePrefixes are the attributes scout relies on to process telemetry,
+// which are kept in allowlist mode.
+var requiredAttributePrefixes = []string{"scout.", "log.", "metric.", "exception."}
+
+// WithAttributeDenylist removes attributes with the given keys from all spans, errors, logs and metrics before export.
+func WithAttributeDenylist(keys ...string) Option {
+	return option(func(conf *config) {
+		if conf.attributeDenylist == nil {
+			conf.attributeDenylist = map[attribute.Key]bool{}
+		}
+		for _, key := range keys {
+			conf.attributeDenylist[attribute.Key(key)] = true
+		}
+	})
+}
+
+// WithAttributeAllowlist only exports attributes with the given keys, plus the attributes scout needs
+// to process errors, logs and metrics (those prefixed with scout., log., metric. and exception.).
+func WithAttributeAllowlist(keys ...string) Option {
+	return option(func(conf *config) {
+		if conf.attributeAllowlist == nil {
+			conf.attributeAllowlist = map[attribute.Key]bool{}
+		}
+		for _, key := range keys {
+			conf.attributeAllowlist[attribute.Key(key)] = true
+		}
+	})
+}
+
+func isRequiredAttribute(key attribute.Key) bool {
+	for _, prefix := range requiredAttributePrefixes {
+		if strings.HasPrefix(string(key), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDataScrubbing redacts common secrets (credit card numbers, SSNs, bearer tokens, AWS access keys and emails)
+// from error messages, log bodies and span status descriptions before export.
+// Additional patterns are redacted alongside the built-in ones.
+func WithDataScrubbing(patterns ...*regexp.Regexp) Option {
+	return option(func(conf *config) {
+		conf.dataScrubbing = true
+		conf.scrubPatterns = append(conf.scrubPatterns, patterns...)
+	})
+}
+
+// WithScrubbedAttributes applies data scrubbing to the values of additional attribute keys.
+func WithScrubbedAttributes(keys ...string) Option {
+	return option(func(conf *config) {
+		for _, key := range keys {
+			conf.scrubbedAttributes = append(conf.scrubbedAttributes, attribute.Key(key))
+		}
+	})
+}
+
+// scrub redacts sensitive data from s.
+func scrub(s string, patterns []*regexp.Regexp) string {
+	s = creditCardPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if !luhnValid(match) {
+			return match
+		}
+		return RedactedValue
+	})
+	for _, pattern := range []*regexp.Regexp{ssnPattern, bearerTokenPattern, awsAccessKeyPattern, emailPattern} {
+		s = pattern.ReplaceAllString(s, RedactedValue)
+	}
+	for _, pattern := range patterns {
+		s = pattern.ReplaceAllString(s, RedactedValue)
+	}
+	return s
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by card numbers.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func dataScrubbingFilter(keys []attribute.Key, patterns []*regexp.Regexp) spanFilter {
+	scrubbed := make(map[attribute.Key]bool, len(keys))
+	for _, key := range keys {
+		scrubbed[key] = true
+	}
+	return func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		filtered := newFilteredSpan(s)
+		filtered.mapAttributes(func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+			if scrubbed[kv.Key] && kv.Value.Type() == attribute.STRING {
+				return kv.Key.String(scrub(kv.Value.AsString(), patterns)), true
+			}
+			return kv, true
+		})
+		filtered.status.Description = scrub(filtered.status.Description, patterns)
+		return filtered
+	}
+}
+
+// WithAnonymization truncates client IP addresses captured by middleware and replaces
+// user identifiers (the enduser.id attribute) with a salted hash before export.
+func WithAnonymization(salt string) Option {
+	return option(func(conf *config) {
+		conf.anonymization = true
+		conf.anonymizationSalt = salt
+	})
+}
+
+// AnonymizeIP zeroes the host part of an IP address: the last octet of IPv4 addresses
+// and the last 80 bits of IPv6 addresses. Values that are not IP addresses are redacted.
+func AnonymizeIP(ip string) string {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return RedactedValue
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// HashIdentifier returns a salted SHA-256 hash of id.
+func HashIdentifier(id, salt string) string {
+	sum := sha256.Sum256([]byte(salt + id))
+	return hex.EncodeToString(sum[:])
+}
+
+func anonymizationFilter(salt string) spanFilter {
+	return func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		filtered := newFilteredSpan(s)
+		filtered.mapAttributes(func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+			switch kv.Key {
+			case semconv.HTTPClientIPKey, semconv.NetSockPeerAddrKey:
+				return kv.Key.String(AnonymizeIP(kv.Value.Emit())), true
+			case semconv.EnduserIDKey:
+				return kv.Key.String(HashIdentifier(kv.Value.Emit(), salt)), true
+			}
+			return kv, true
+		})
+		return filtered
+	}
+}
+
+// WithRedactedQueryParams redacts the values of additional query parameters from URLs recorded by middleware.
+// Parameters are matched case-insensitively, on top of a default list of common credential parameters.
+func WithRedactedQueryParams(params ...string) Option {
+	return runtimeOption(func(conf *config) {
+		for _, param := range params {
+			conf.redactedQueryParams = append(conf.redactedQueryParams, strings.ToLower(param))
+		}
+	})
+}
+
+func isRedactedQueryParam(param string) bool {
+	conf := loadConfig()
+	param = strings.ToLower(param)
+	for _, redacted := range defaultRedactedQueryParams {
+		if param == redacted {
+			return true
+		}
+	}
+	for _, redacted := range conf.redactedQueryParams {
+		if param == redacted {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL returns a copy of u with basic-auth credentials and the values of sensitive query parameters
+// redacted. The order of query parameters is preserved.
+func RedactURL(u *url.URL) *url.URL {
+	if u == nil || u.RawQuery == "" && u.User == nil {
+		return u
+	}
+	redacted := *u
+	if u.User != nil {
+		redacted.User = url.User(RedactedValue)
+	}
+	if u.RawQuery == "" {
+		return &redacted
+	}
+	pairs := strings.Split(u.RawQuery, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if name, err := url.QueryUnescape(key); err == nil && isRedactedQueryParam(name) {
+			pairs[i] = key + "=" + RedactedValue
+		}
+	}
+	redacted.RawQuery = strings.Join(pairs, "&")
+	return &redacted
+}
@@ -0,0 +1,199 @@
+// Package sentry provides a subset of the sentry-go API backed by Scout,
+// so call sites can be migrated by swapping the import path.
+package sentry
+
+import (
+	"context"
+	"crypto/rand"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/scout-inc/scout-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventID identifies a captured event. It is the trace ID of the trace the event was recorded within.
+type EventID string
+
+// Scope holds the tags applied to captured events.
+type Scope struct {
+	mu   sync.RWMutex
+	tags map[string]string
+}
+
+// NewScope returns an empty scope.
+func NewScope() *Scope {
+	return &Scope{tags: map[string]string{}}
+}
+
+// SetTag adds a tag to the scope.
+func (s *Scope) SetTag(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[key] = value
+}
+
+// SetTags adds multiple tags to the scope.
+func (s *Scope) SetTags(tags map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range tags {
+		s.tags[k] = v
+	}
+}
+
+// RemoveTag removes a tag from the scope.
+func (s *Scope) RemoveTag(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tags, key)
+}
+
+// Clone returns a copy of the scope.
+func (s *Scope) Clone() *Scope {
+	clone := NewScope()
+	clone.SetTags(s.tagsCopy())
+	return clone
+}
+
+func (s *Scope) tagsCopy() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tags := make(map[string]string, len(s.tags))
+	for k, v := range s.tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+func (s *Scope) attributes() []attribute.KeyValue {
+	tags := s.tagsCopy()
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+	return attrs
+}
+
+// scopes is the stack of scopes of the running WithScope calls, mirroring the scope stack of sentry-go's
+// global hub. The first scope is shared by every event and is never popped.
+var scopes = struct {
+	mu    sync.Mutex
+	stack []*Scope
+}{stack: []*Scope{NewScope()}}
+
+// currentScope returns the scope at the top of the stack.
+func currentScope() *Scope {
+	scopes.mu.Lock()
+	defer scopes.mu.Unlock()
+	return scopes.stack[len(scopes.stack)-1]
+}
+
+func pushScope(scope *Scope) {
+	scopes.mu.Lock()
+	defer scopes.mu.Unlock()
+	scopes.stack = append(scopes.stack, scope)
+}
+
+// popScope removes scope from the stack. As with a shared hub, concurrent WithScope calls may not
+// return in the order they were pushed, so scope is looked up rather than assumed to be at the top.
+func popScope(scope *Scope) {
+	scopes.mu.Lock()
+	defer scopes.mu.Unlock()
+	for i := len(scopes.stack) - 1; i > 0; i-- {
+		if scopes.stack[i] == scope {
+			scopes.stack = slices.Delete(scopes.stack, i, i+1)
+			return
+		}
+	}
+}
+
+type scopeKey struct{}
+
+// ContextWithScope returns a copy of ctx carrying scope, whose tags are applied to the events captured with
+// the context rather than those of the current scope.
+func ContextWithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// scopeOf returns the scope carried by ctx, or the current scope.
+func scopeOf(ctx context.Context) *Scope {
+	if scope, ok := ctx.Value(scopeKey{}).(*Scope); ok {
+		return scope
+	}
+	return currentScope()
+}
+
+// WithScope runs f with a copy of the current scope, which is the current scope until f returns, so
+// the events captured within f are tagged with it:
+//
+//	sentry.WithScope(func(scope *sentry.Scope) {
+//		scope.SetTag("invoice", invoice.ID)
+//		sentry.CaptureException(err)
+//	})
+//
+// As with sentry-go's global hub, the current scope is shared by goroutines, so goroutines calling
+// WithScope concurrently should capture with a context carrying their scope, see ContextWithScope.
+func WithScope(f func(scope *Scope)) {
+	scope := currentScope().Clone()
+	pushScope(scope)
+	defer popScope(scope)
+	f(scope)
+}
+
+// ConfigureScope runs f with the current scope, so changes apply to all subsequent events.
+func ConfigureScope(f func(scope *Scope)) {
+	f(currentScope())
+}
+
+// SetTag adds a tag to the current scope.
+func SetTag(key, value string) {
+	currentScope().SetTag(key, value)
+}
+
+// SetTags adds multiple tags to the current scope.
+func SetTags(tags map[string]string) {
+	currentScope().SetTags(tags)
+}
+
+// CaptureException records err as a Scout error tagged with the current scope.
+func CaptureException(err error) *EventID {
+	return CaptureExceptionWithContext(context.Background(), err)
+}
+
+// CaptureExceptionWithContext is CaptureException, associating the error with the Scout session and trace in ctx,
+// and tagging it with the scope of ctx set with ContextWithScope, if any.
+func CaptureExceptionWithContext(ctx context.Context, err error) *EventID {
+	if err == nil {
+		return nil
+	}
+	ctx = scout.RecordError(ctx, err, scopeOf(ctx).attributes()...)
+	return eventID(ctx)
+}
+
+// CaptureMessage records message as a Scout log tagged with the current scope.
+func CaptureMessage(message string) *EventID {
+	return CaptureMessageWithContext(context.Background(), message)
+}
+
+// CaptureMessageWithContext is CaptureMessage, associating the message with the Scout session and trace in ctx,
+// and tagging it with the scope of ctx set with ContextWithScope, if any.
+func CaptureMessageWithContext(ctx context.Context, message string) *EventID {
+	scout.RecordLog(ctx, scout.LogRecord{Severity: "INFO", Message: message, Attributes: scopeOf(ctx).attributes()})
+	return eventID(ctx)
+}
+
+// eventID returns the trace ID of the span in ctx, or a random ID for an event recorded outside of a trace.
+func eventID(ctx context.Context) *EventID {
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	if !traceID.IsValid() {
+		_, _ = rand.Read(traceID[:])
+	}
+	id := EventID(traceID.String())
+	return &id
+}
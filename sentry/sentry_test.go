@@ -0,0 +1,92 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scout-inc/scout-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	scout.Start(scout.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), scout.WithoutOTLPExporter())
+	code := m.Run()
+	scout.Stop()
+	os.Exit(code)
+}
+
+func TestWithScope(t *testing.T) {
+	SetTag("service", "billing")
+	WithScope(func(scope *Scope) {
+		scope.SetTag("invoice", "123")
+		ctx := ContextWithScope(context.Background(), scope)
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("invoice", "123"),
+			attribute.String("service", "billing"),
+		}, scopeOf(ctx).attributes())
+	})
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("service", "billing"),
+	}, scopeOf(context.Background()).attributes())
+}
+
+func TestWithScopeConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(invoice string) {
+			defer wg.Done()
+			WithScope(func(scope *Scope) {
+				scope.SetTag("invoice", invoice)
+				time.Sleep(time.Millisecond)
+				assert.Contains(t, scope.attributes(), attribute.String("invoice", invoice))
+			})
+		}(strconv.Itoa(i))
+	}
+	wg.Wait()
+	assert.NotContains(t, scopeOf(context.Background()).tagsCopy(), "invoice")
+}
+
+func TestCapture(t *testing.T) {
+	ended := len(recorder.Ended())
+	assert.Nil(t, CaptureException(nil))
+	assert.NotNil(t, CaptureException(errors.New("capture failed")))
+	id := CaptureMessage("captured")
+	require.NotNil(t, id)
+	assert.NotEqual(t, EventID("00000000000000000000000000000000"), *id)
+
+	spans := recorder.Ended()[ended:]
+	require.Len(t, spans, 2)
+	assert.Contains(t, spans[0].Events()[0].Attributes, semconv.ExceptionMessageKey.String("capture failed"))
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, scout.LogEvent, spans[1].Events()[0].Name)
+	assert.Contains(t, spans[1].Events()[0].Attributes, attribute.String(scout.LogMessageAttribute, "captured"))
+}
+
+func TestCaptureWithScope(t *testing.T) {
+	ended := len(recorder.Ended())
+	WithScope(func(scope *Scope) {
+		scope.SetTag("invoice", "123")
+		CaptureException(errors.New("capture failed"))
+		CaptureMessage("captured")
+	})
+	CaptureException(errors.New("capture failed"))
+
+	spans := recorder.Ended()[ended:]
+	require.Len(t, spans, 3)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("invoice", "123"))
+	assert.Contains(t, spans[1].Events()[0].Attributes, attribute.String("invoice", "123"))
+	assert.NotContains(t, spans[2].Attributes(), attribute.String("invoice", "123"))
+}
@@ -0,0 +1,95 @@
+package scout
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+)
+
+// ShutdownSummary reports how the telemetry buffered when Scout stopped was flushed,
+// so deploy tooling can verify a clean shutdown.
+type ShutdownSummary struct {
+	// SpansFlushed is the number of spans exported while shutting down.
+	SpansFlushed int64
+	// SpansDropped is the number of spans that failed to export while shutting down,
+	// or were still waiting to be exported once it completed.
+	SpansDropped int64
+	// LogsFlushed is the number of logs exported with the logs signal while shutting down.
+	LogsFlushed int64
+	// LogsDropped is the number of logs that failed to export or were dropped while shutting down,
+	// or were still waiting to be exported once it completed.
+	LogsDropped int64
+	// Duration is how long flushing took.
+	Duration time.Duration
+	// LastError is the last error flushing or exporting spans and logs, if any.
+	LastError error
+}
+
+// exportCounts are the spans and logs exported and failed across the exporters.
+type exportCounts struct {
+	spansExported, spansFailed int64
+	logsExported, logsFailed   int64
+}
+
+// exportTotals sums the spans and logs exported and failed across the exporters.
+func exportTotals() exportCounts {
+	var totals exportCounts
+	for _, stats := range exporterStatsSnapshot() {
+		if stats.logs {
+			totals.logsExported += stats.exported.Load()
+			totals.logsFailed += stats.failed.Load()
+		} else {
+			totals.spansExported += stats.exported.Load()
+			totals.spansFailed += stats.failed.Load()
+		}
+	}
+	return totals
+}
+
+// flushTelemetry flushes the spans and logs buffered by the exporters,
+// summarizing the exports since the totals were taken.
+func flushTelemetry(ctx context.Context, o *OTLP, start time.Time, before exportCounts) ShutdownSummary {
+	summary := ShutdownSummary{LastError: o.flush(ctx)}
+	summary.Duration = time.Since(start)
+
+	after := exportTotals()
+	summary.SpansFlushed = after.spansExported - before.spansExported
+	summary.SpansDropped = after.spansFailed - before.spansFailed
+	summary.LogsFlushed = after.logsExported - before.logsExported
+	summary.LogsDropped = after.logsFailed - before.logsFailed
+	var lastExport time.Time
+	var lastExportErr error
+	for _, stats := range exporterStatsSnapshot() {
+		if stats.logs {
+			summary.LogsDropped += max(stats.pending(), 0)
+		} else {
+			summary.SpansDropped += max(stats.pending(), 0)
+		}
+		if at, err := stats.lastResult(); err != nil && at.After(lastExport) {
+			lastExport, lastExportErr = at, err
+		}
+	}
+	if summary.LastError == nil {
+		summary.LastError = lastExportErr
+	}
+	return summary
+}
+
+// recordShutdownSummary logs the summary and records it as a final self-metric,
+// which is exported as the tracer provider shuts down.
+func recordShutdownSummary(summary ShutdownSummary) {
+	if summary.LastError != nil {
+		logger.Errorf("flushed %d spans and %d logs in %s, dropped %d spans and %d logs: %s", summary.SpansFlushed, summary.LogsFlushed, summary.Duration, summary.SpansDropped, summary.LogsDropped, summary.LastError)
+	} else {
+		logger.Infof("flushed %d spans and %d logs in %s, dropped %d spans and %d logs", summary.SpansFlushed, summary.LogsFlushed, summary.Duration, summary.SpansDropped, summary.LogsDropped)
+	}
+
+	span, _ := startInternalTrace(context.Background(), ScopedKey("shutdown", ptr.String("-")))
+	addGaugeEvent(span, "scout.shutdown.flushed", float64(summary.SpansFlushed))
+	addGaugeEvent(span, "scout.shutdown.dropped", float64(summary.SpansDropped))
+	addGaugeEvent(span, "scout.shutdown.logs.flushed", float64(summary.LogsFlushed))
+	addGaugeEvent(span, "scout.shutdown.logs.dropped", float64(summary.LogsDropped))
+	addGaugeEvent(span, "scout.shutdown.duration", summary.Duration.Seconds())
+	EndTrace(span)
+}
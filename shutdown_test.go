@@ -0,0 +1,34 @@
+package scout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestFlushTelemetry(t *testing.T) {
+	useConfig(t, &config{})
+	t.Cleanup(resetExporterStats)
+
+	resetExporterStats()
+	exporter := &recordingExporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(newExportProcessor(exporter, &exporterStats{name: "test"})))
+	o := &OTLP{tracerProvider: provider, ownsProvider: true}
+
+	start := time.Now()
+	totals := exportTotals()
+	for _, name := range []string{"a", "b"} {
+		_, span := provider.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+
+	summary := flushTelemetry(context.Background(), o, start, totals)
+	assert.Equal(t, int64(2), summary.SpansFlushed)
+	assert.Zero(t, summary.SpansDropped)
+	assert.NoError(t, summary.LastError)
+	assert.Positive(t, summary.Duration)
+	assert.Equal(t, []string{"a", "b"}, exporter.names())
+}
@@ -0,0 +1,60 @@
+package scout
+
+import (
+	"regexp"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SlowAttribute = "scout.slow"
+
+type slowSpanThreshold struct {
+	pattern   *regexp.Regexp
+	threshold time.Duration
+}
+
+// WithSlowSpanThreshold flags spans whose name matches pattern and that last at least threshold
+// with a scout.slow=true attribute. The first matching threshold applies, in the order they are set.
+//
+// For example, to flag slow database queries:
+//
+//	scout.Init(scout.WithSlowSpanThreshold(regexp.MustCompile(`^db\.`), 200*time.Millisecond))
+func WithSlowSpanThreshold(pattern *regexp.Regexp, threshold time.Duration) Option {
+	return option(func(conf *config) {
+		conf.slowSpanThresholds = append(conf.slowSpanThresholds, slowSpanThreshold{pattern: pattern, threshold: threshold})
+	})
+}
+
+// WithSlowSpanSampling exports spans flagged by WithSlowSpanThreshold even when they were sampled out,
+// so slow outliers are retained under aggressive sampling. Spans that are sampled out are then still
+// recorded until they end, which costs more than dropping them when they start.
+// It does not apply to a provider supplied with WithTracerProvider.
+func WithSlowSpanSampling() Option {
+	return option(func(conf *config) {
+		conf.slowSpanSampling = true
+	})
+}
+
+func slowSpanFilter(thresholds []slowSpanThreshold, forceSample bool) spanFilter {
+	return func(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		duration := s.EndTime().Sub(s.StartTime())
+		for _, t := range thresholds {
+			if !t.pattern.MatchString(s.Name()) {
+				continue
+			}
+			if duration < t.threshold {
+				return s
+			}
+			filtered := newFilteredSpan(s)
+			filtered.attributes = append(filtered.attributes, attribute.Bool(SlowAttribute, true))
+			if forceSample && !filtered.spanContext.IsSampled() {
+				filtered.spanContext = filtered.spanContext.WithTraceFlags(filtered.spanContext.TraceFlags() | trace.FlagsSampled)
+			}
+			return filtered
+		}
+		return s
+	}
+}
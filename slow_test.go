@@ -0,0 +1,67 @@
+package scout
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func timedSpan(name string, duration time.Duration, flags trace.TraceFlags) sdktrace.ReadOnlySpan {
+	start := time.Now()
+	return tracetest.SpanStub{
+		Name:      name,
+		StartTime: start,
+		EndTime:   start.Add(duration),
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{1},
+			TraceFlags: flags,
+		}),
+	}.Snapshot()
+}
+
+func TestSlowSpanFilter(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	thresholds := []slowSpanThreshold{
+		{regexp.MustCompile(`^db\.`), 100 * time.Millisecond},
+		{regexp.MustCompile(`.*`), time.Second},
+	}
+	p := filterProcessor{next: recorder, filters: []spanFilter{slowSpanFilter(thresholds, true)}, stats: []*exporterStats{{}}}
+
+	p.OnEnd(timedSpan("db.query", 150*time.Millisecond, trace.FlagsSampled))
+	p.OnEnd(timedSpan("db.query", 50*time.Millisecond, trace.FlagsSampled))
+	p.OnEnd(timedSpan("http.request", 150*time.Millisecond, trace.FlagsSampled))
+	p.OnEnd(timedSpan("db.query", 150*time.Millisecond, 0))
+	p.OnEnd(timedSpan("db.query", 50*time.Millisecond, 0))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+	slow := attribute.Bool(SlowAttribute, true)
+	for i, expected := range []struct{ slow, sampled bool }{
+		{true, true},
+		{false, true},
+		{false, true},
+		{true, true},
+		{false, false},
+	} {
+		assert.Equal(t, expected.slow, contains(spans[i].Attributes(), slow), i)
+		assert.Equal(t, expected.sampled, spans[i].SpanContext().IsSampled(), i)
+	}
+	assert.Equal(t, int64(4), p.stats[0].queued.Load())
+}
+
+func contains(attrs []attribute.KeyValue, kv attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == kv {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package scout
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exporterStats tracks the health of an exporter for diagnostics.
+type exporterStats struct {
+	name     string
+	endpoint string
+	// logs marks the stats of the log exporter, which count logs rather than spans
+	logs bool
+	// queued counts spans handed to the exporter's batching processor
+	queued   atomic.Int64
+	exported atomic.Int64
+	failed   atomic.Int64
+
+	mu sync.Mutex
+	// lastError is the error of the most recent export, if it failed
+	lastError  error
+	lastExport time.Time
+}
+
+var (
+	statsMutex      sync.RWMutex
+	activeExporters []*exporterStats
+)
+
+func registerExporterStats(stats *exporterStats) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+	activeExporters = append(activeExporters, stats)
+}
+
+func resetExporterStats() {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+	activeExporters = nil
+}
+
+func exporterStatsSnapshot() []*exporterStats {
+	statsMutex.RLock()
+	defer statsMutex.RUnlock()
+	return append([]*exporterStats{}, activeExporters...)
+}
+
+// pending approximates the number of spans waiting to be exported.
+func (s *exporterStats) pending() int64 {
+	return s.queued.Load() - s.exported.Load() - s.failed.Load()
+}
+
+func (s *exporterStats) lastResult() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastExport, s.lastError
+}
+
+// instrumentedExporter records export outcomes in its stats and bounds each export by the export timeout.
+type instrumentedExporter struct {
+	sdktrace.SpanExporter
+	stats   *exporterStats
+	timeout time.Duration
+}
+
+func (e instrumentedExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.stats.mu.Lock()
+	defer e.stats.mu.Unlock()
+	e.stats.lastExport = time.Now()
+	if err != nil {
+		e.stats.failed.Add(int64(len(spans)))
+		e.stats.lastError = err
+		return err
+	}
+	e.stats.exported.Add(int64(len(spans)))
+	e.stats.lastError = nil
+	return nil
+}
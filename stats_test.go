@@ -0,0 +1,26 @@
+package scout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type blockingExporter struct {
+	recordingExporter
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, _ []sdktrace.ReadOnlySpan) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestExportTimeout(t *testing.T) {
+	exporter := instrumentedExporter{SpanExporter: &blockingExporter{}, stats: &exporterStats{}, timeout: time.Millisecond}
+	err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{stubSpan("a", "")})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(1), exporter.stats.failed.Load())
+}
@@ -0,0 +1,35 @@
+package scout
+
+// StatusClassifier reports whether a response status marks the span of a request to route as an error.
+type StatusClassifier func(route string, status int) bool
+
+// DefaultStatusClassifier marks server errors, statuses 500 and above, as errors.
+func DefaultStatusClassifier(_ string, status int) bool {
+	return status >= 500
+}
+
+// WithStatusClassifier sets which response statuses mark the spans of middleware as errors, instead of
+// DefaultStatusClassifier. For example, to also treat rate limiting as an error, except on a route
+// where it is expected:
+//
+//	scout.WithStatusClassifier(func(route string, status int) bool {
+//		if status == http.StatusTooManyRequests {
+//			return route != "/poll"
+//		}
+//		return scout.DefaultStatusClassifier(route, status)
+//	})
+func WithStatusClassifier(classifier StatusClassifier) Option {
+	return runtimeOption(func(conf *config) {
+		conf.statusClassifier = classifier
+	})
+}
+
+// IsErrorStatus reports whether the response status of a request to route is an error,
+// according to the classifier set by WithStatusClassifier.
+func IsErrorStatus(route string, status int) bool {
+	conf := loadConfig()
+	if conf.statusClassifier != nil {
+		return conf.statusClassifier(route, status)
+	}
+	return DefaultStatusClassifier(route, status)
+}
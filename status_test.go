@@ -0,0 +1,46 @@
+package scout
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsErrorStatus(t *testing.T) {
+	useConfig(t, &config{})
+
+	tests := []struct {
+		route    string
+		status   int
+		expected bool
+	}{
+		{"/users", http.StatusOK, false},
+		{"/users", http.StatusNotFound, false},
+		{"/users", http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		if result := IsErrorStatus(tt.route, tt.status); result != tt.expected {
+			t.Fatalf("[IsErrorStatus] expected %d to be an error: %t", tt.status, tt.expected)
+		}
+	}
+
+	updateConfig(WithStatusClassifier(func(route string, status int) bool {
+		if status == http.StatusTooManyRequests {
+			return route != "/poll"
+		}
+		return DefaultStatusClassifier(route, status)
+	}).apply)
+	tests = []struct {
+		route    string
+		status   int
+		expected bool
+	}{
+		{"/users", http.StatusTooManyRequests, true},
+		{"/poll", http.StatusTooManyRequests, false},
+		{"/poll", http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		if result := IsErrorStatus(tt.route, tt.status); result != tt.expected {
+			t.Fatalf("[IsErrorStatus] expected %d on %s to be an error: %t", tt.status, tt.route, tt.expected)
+		}
+	}
+}
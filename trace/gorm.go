@@ -9,7 +9,6 @@ import (
 	"github.com/scout-inc/scout-go"
 
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
@@ -17,6 +16,9 @@ import (
 
 var dbRowsAffected = attribute.Key("db.rows_affected")
 
+// gormSpanKey holds the span of a statement in its gorm instance settings.
+const gormSpanKey = "scout:span"
+
 type otelPlugin struct {
 	attrs            []attribute.KeyValue
 	excludeQueryVars bool
@@ -82,13 +84,24 @@ func (p otelPlugin) Initialize(db *gorm.DB) (err error) {
 
 func (p *otelPlugin) before(spanName string) gormHookFunc {
 	return func(tx *gorm.DB) {
-		_, tx.Statement.Context = scout.StartTrace(tx.Statement.Context, spanName)
+		if !scout.IntegrationEnabled(scout.IntegrationGorm) {
+			return
+		}
+		var span trace.Span
+		span, tx.Statement.Context = scout.StartTrace(tx.Statement.Context, spanName)
+		tx.InstanceSet(gormSpanKey, span)
 	}
 }
 
 func (p *otelPlugin) after() gormHookFunc {
 	return func(tx *gorm.DB) {
-		span := trace.SpanFromContext(tx.Statement.Context)
+		// the span is handed over explicitly, so a statement run while the integration was disabled
+		// does not end the span of the caller
+		value, _ := tx.InstanceGet(gormSpanKey)
+		span, ok := value.(trace.Span)
+		if !ok {
+			return
+		}
 		defer scout.EndTrace(span)
 
 		attrs := make([]attribute.KeyValue, 0, len(p.attrs)+4)
@@ -125,7 +138,7 @@ func (p *otelPlugin) after() gormHookFunc {
 			// ignore
 		default:
 			span.RecordError(tx.Error)
-			span.SetStatus(codes.Error, tx.Error.Error())
+			scout.SetSpanErrorStatus(span, tx.Error)
 		}
 	}
 }
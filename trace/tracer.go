@@ -48,6 +48,9 @@ func (t Tracer) Validate(graphql.ExecutableSchema) error {
 
 // InterceptField instruments timing of individual fields resolved.
 func (t Tracer) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	if !scout.IntegrationEnabled(scout.IntegrationGraphQL) {
+		return next(ctx)
+	}
 	fc := graphql.GetFieldContext(ctx)
 	fieldName := fc.Field.Name
 	name := fmt.Sprintf("graphql.field.%s", fieldName)
@@ -73,6 +76,9 @@ func (t Tracer) InterceptField(ctx context.Context, next graphql.Resolver) (inte
 // InterceptResponse instruments timing, payload size, and error information
 // of the response handler. The metric is grouped by the corresponding operation name.
 func (t Tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !scout.IntegrationEnabled(scout.IntegrationGraphQL) {
+		return next(ctx)
+	}
 	var oc *graphql.OperationContext
 	if graphql.HasOperationContext(ctx) {
 		oc = graphql.GetOperationContext(ctx)
@@ -108,7 +114,7 @@ func GraphQLRecoverFunc() graphql.RecoverFunc {
 		if e, ok = err.(error); !ok {
 			e = errors.Errorf("panic {error: %+v}", err)
 		}
-		scout.RecordError(ctx, e, attribute.String(scout.SourceAttribute, "GraphQLRecoverFunc"))
+		scout.RecordPanic(ctx, e, attribute.String(scout.SourceAttribute, "GraphQLRecoverFunc"))
 		return e
 	}
 }
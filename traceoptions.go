@@ -0,0 +1,120 @@
+package scout
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceOption configures a span started by StartTraceWithOptions.
+type TraceOption interface {
+	applyTrace(cfg *traceConfig)
+}
+
+type traceOption func(cfg *traceConfig)
+
+func (fn traceOption) applyTrace(cfg *traceConfig) {
+	fn(cfg)
+}
+
+type traceConfig struct {
+	timestamp                 time.Time
+	startOptions              []trace.SpanStartOption
+	withoutResourceAttributes bool
+	tags                      []attribute.KeyValue
+}
+
+func newTraceConfig(opts []TraceOption) traceConfig {
+	var cfg traceConfig
+	for _, opt := range opts {
+		opt.applyTrace(&cfg)
+	}
+	if cfg.timestamp.IsZero() {
+		cfg.timestamp = time.Now()
+	}
+	return cfg
+}
+
+// WithTimestamp sets the start time of the span. It defaults to the current time.
+func WithTimestamp(t time.Time) TraceOption {
+	return traceOption(func(cfg *traceConfig) {
+		cfg.timestamp = t
+	})
+}
+
+// WithSpanKindOption sets the kind of the span. AsServer, AsClient, AsProducer, AsConsumer and AsInternal are shorthands for it.
+func WithSpanKindOption(kind trace.SpanKind) TraceOption {
+	return WithSpanStartOptions(trace.WithSpanKind(kind))
+}
+
+// WithoutResourceAttrs blanks out the resource attributes of the span,
+// for telemetry submitted on behalf of another service.
+func WithoutResourceAttrs() TraceOption {
+	return traceOption(func(cfg *traceConfig) {
+		cfg.withoutResourceAttributes = true
+	})
+}
+
+// WithTags sets attributes on the span. Tags take precedence over the project, session and request IDs set by scout.
+func WithTags(tags ...attribute.KeyValue) TraceOption {
+	return traceOption(func(cfg *traceConfig) {
+		cfg.tags = append(cfg.tags, tags...)
+	})
+}
+
+// WithSpanStartOptions passes OpenTelemetry span start options through to the tracer.
+func WithSpanStartOptions(opts ...trace.SpanStartOption) TraceOption {
+	return traceOption(func(cfg *traceConfig) {
+		cfg.startOptions = append(cfg.startOptions, opts...)
+	})
+}
+
+// AsServer marks the span as handling an incoming request.
+func AsServer() TraceOption {
+	return WithSpanKindOption(trace.SpanKindServer)
+}
+
+// AsClient marks the span as making an outgoing request.
+func AsClient() TraceOption {
+	return WithSpanKindOption(trace.SpanKindClient)
+}
+
+// AsProducer marks the span as publishing a message for asynchronous processing.
+func AsProducer() TraceOption {
+	return WithSpanKindOption(trace.SpanKindProducer)
+}
+
+// AsConsumer marks the span as processing a message published by a producer.
+func AsConsumer() TraceOption {
+	return WithSpanKindOption(trace.SpanKindConsumer)
+}
+
+// AsInternal marks the span as an internal operation.
+func AsInternal() TraceOption {
+	return WithSpanKindOption(trace.SpanKindInternal)
+}
+
+// WithLinks links the span to other spans, such as the spans that produced the messages a batch
+// consumer is processing. Links to invalid span contexts are ignored. For example:
+//
+//	links := make([]trace.Link, len(messages))
+//	for i, msg := range messages {
+//		links[i] = scout.LinkFromContext(msg.Context())
+//	}
+//	span, ctx := scout.StartTraceWithOptions(ctx, "process-batch", scout.AsConsumer(), scout.WithLinks(links...))
+func WithLinks(links ...trace.Link) TraceOption {
+	valid := make([]trace.Link, 0, len(links))
+	for _, link := range links {
+		if link.SpanContext.IsValid() {
+			valid = append(valid, link)
+		}
+	}
+	return WithSpanStartOptions(trace.WithLinks(valid...))
+}
+
+// LinkFromContext returns a link to the span in ctx, for use with WithLinks.
+func LinkFromContext(ctx context.Context, attrs ...attribute.KeyValue) trace.Link {
+	return trace.LinkFromContext(ctx, attrs...)
+}
@@ -0,0 +1,101 @@
+package scout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartTraceWithOptions(t *testing.T) {
+	recorder := recordSpans(t)
+	prev := loadState()
+	storeState(idle)
+	defer storeState(prev)
+	ctx := ContextWithRequestID(ContextWithSessionID(context.Background(), "session"), "cmVxdWVzdA==")
+	start := time.Now().Add(-time.Minute)
+
+	span, _ := StartTraceWithOptions(ctx, "replayed", WithTimestamp(start))
+	EndTrace(span)
+	span, _ = StartTraceWithOptions(ctx, "forwarded", WithoutResourceAttrs())
+	EndTrace(span)
+	span, _ = StartTraceWithOptions(ctx, "tagged",
+		WithTags(attribute.String("tenant", "acme"), attribute.String(SessionIDAttribute, "tagged")),
+		WithSpanStartOptions(trace.WithAttributes(attribute.String("tenant", "start"), attribute.String("region", "eu"))),
+	)
+	EndTrace(span)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, start, spans[0].StartTime())
+	assert.NotContains(t, spans[0].Attributes(), semconv.ServiceNameKey.String(""))
+
+	// the resource attributes are blanked out, while the session and request still attribute the span
+	forwarded := spans[1].Attributes()
+	assert.WithinDuration(t, time.Now(), spans[1].StartTime(), time.Minute)
+	assert.Contains(t, forwarded, semconv.ServiceNameKey.String(""))
+	assert.Contains(t, forwarded, semconv.HostNameKey.String(""))
+	assert.Contains(t, forwarded, attribute.String(SessionIDAttribute, "session"))
+	assert.Contains(t, forwarded, attribute.String(RequestIDAttribute, "cmVxdWVzdA=="))
+
+	// tags are set after the attributes of the start options and the IDs set by scout, so they win
+	tagged := spans[2].Attributes()
+	assert.Contains(t, tagged, attribute.String("tenant", "acme"))
+	assert.NotContains(t, tagged, attribute.String("tenant", "start"))
+	assert.Contains(t, tagged, attribute.String("region", "eu"))
+	assert.Contains(t, tagged, attribute.String(SessionIDAttribute, "tagged"))
+	assert.NotContains(t, tagged, attribute.String(SessionIDAttribute, "session"))
+}
+
+func TestWithLinks(t *testing.T) {
+	recorder := recordSpans(t)
+
+	producer, producerCtx := StartTraceWithOptions(context.Background(), "publish", AsProducer())
+	EndTrace(producer)
+	consumer, _ := StartTraceWithOptions(context.Background(), "process", AsConsumer(),
+		WithLinks(LinkFromContext(producerCtx, String("messaging.message.id", "1")), LinkFromContext(context.Background())))
+	EndTrace(consumer)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	links := spans[1].Links()
+	require.Len(t, links, 1)
+	assert.Equal(t, producer.SpanContext(), links[0].SpanContext)
+	assert.Equal(t, trace.SpanKindConsumer, spans[1].SpanKind())
+}
+
+func TestSpanKindOptions(t *testing.T) {
+	recorder := recordSpans(t)
+	tests := map[string]struct {
+		opts     []TraceOption
+		expected trace.SpanKind
+	}{
+		"server":   {opts: []TraceOption{AsServer()}, expected: trace.SpanKindServer},
+		"client":   {opts: []TraceOption{AsClient()}, expected: trace.SpanKindClient},
+		"producer": {opts: []TraceOption{AsProducer()}, expected: trace.SpanKindProducer},
+		"consumer": {opts: []TraceOption{AsConsumer()}, expected: trace.SpanKindConsumer},
+		"internal": {opts: []TraceOption{AsInternal()}, expected: trace.SpanKindInternal},
+		"last kind wins over the kind option": {
+			opts:     []TraceOption{WithSpanKindOption(trace.SpanKindClient), AsServer()},
+			expected: trace.SpanKindServer,
+		},
+		"kind option wins over an earlier kind": {
+			opts:     []TraceOption{AsProducer(), WithSpanKindOption(trace.SpanKindConsumer)},
+			expected: trace.SpanKindConsumer,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			span, _ := StartTraceWithOptions(context.Background(), name, tt.opts...)
+			EndTrace(span)
+			spans := recorder.Ended()
+			assert.Equal(t, tt.expected, spans[len(spans)-1].SpanKind())
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 const (
 	ScoutInternalLogTag = "[scout-go]"
 	RequestTracerHeader = "X-Scout-Request"
+	// RequestIDResponseHeader echoes request IDs generated by Scout, see WithRequestIDGeneration
+	RequestIDResponseHeader = "X-Scout-Request-Id"
 )
 
 func ExtractIdsFromRequest(requestDetails string) (string, string, error) {
@@ -0,0 +1,65 @@
+package scout
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const ContainerMemoryUsageAttribute = "container.memory.usage"
+const ContainerMemoryLimitAttribute = "container.memory.limit"
+const ProfileTriggerAttribute = "profile.trigger"
+
+// heapWatchdogInterval is how often the watchdog checks the container memory usage.
+const heapWatchdogInterval = 5 * time.Second
+
+// WithHeapWatchdog captures a heap profile when the container memory usage crosses threshold,
+// a fraction of the cgroup memory limit such as 0.9, so the cause of an OOM kill can be found.
+// The profile is recorded as a Scout profile event. Another profile is only captured once usage has
+// dropped back below the threshold. It does nothing when the container has no memory limit.
+func WithHeapWatchdog(threshold float64) Option {
+	return option(func(conf *config) {
+		conf.heapWatchdogThreshold = threshold
+	})
+}
+
+func startHeapWatchdog() {
+	conf := loadConfig()
+	if conf.heapWatchdogThreshold <= 0 {
+		return
+	}
+	if _, _, ok := cgroupMemory(); !ok {
+		logger.Warnf("heap watchdog disabled: no cgroup memory limit found")
+		return
+	}
+	w := &heapWatchdog{threshold: conf.heapWatchdogThreshold}
+	startPeriodic(heapWatchdogInterval, w.check)
+}
+
+type heapWatchdog struct {
+	threshold float64
+	// triggered is set once a profile is captured and cleared when usage drops below the threshold
+	triggered bool
+}
+
+func (w *heapWatchdog) check(ctx context.Context) {
+	usage, limit, ok := cgroupMemory()
+	if !ok {
+		return
+	}
+	if float64(usage) < w.threshold*float64(limit) {
+		w.triggered = false
+		return
+	}
+	if w.triggered {
+		return
+	}
+	w.triggered = true
+	logger.Warnf("memory usage of %d bytes crossed %.0f%% of the %d byte limit, capturing heap profile", usage, 100*w.threshold, limit)
+	captureProfile(ctx, ProfileHeap,
+		attribute.String(ProfileTriggerAttribute, "memory_threshold"),
+		attribute.Int64(ContainerMemoryUsageAttribute, usage),
+		attribute.Int64(ContainerMemoryLimitAttribute, limit),
+	)
+}
@@ -0,0 +1,59 @@
+package scout
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCgroupFiles(t *testing.T, files map[string]string) {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0o644))
+	}
+	prev := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = prev })
+}
+
+func TestCgroupMemory(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+		usage int64
+		limit int64
+		ok    bool
+	}{
+		"v2":           {map[string]string{"memory.max": "1000", "memory.current": "600"}, 600, 1000, true},
+		"v2 unlimited": {map[string]string{"memory.max": "max", "memory.current": "600"}, 0, 0, false},
+		"v1":           {map[string]string{"memory/memory.limit_in_bytes": "2000", "memory/memory.usage_in_bytes": "500"}, 500, 2000, true},
+		"v1 unlimited": {map[string]string{"memory/memory.limit_in_bytes": "9223372036854771712", "memory/memory.usage_in_bytes": "500"}, 0, 0, false},
+		"none":         {map[string]string{}, 0, 0, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			writeCgroupFiles(t, tt.files)
+			usage, limit, ok := cgroupMemory()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.usage, usage)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestHeapWatchdog(t *testing.T) {
+	recorder := recordSpans(t)
+	w := &heapWatchdog{threshold: 0.9}
+
+	for _, usage := range []string{"800", "950", "990", "500", "950"} {
+		writeCgroupFiles(t, map[string]string{"memory.max": "1000", "memory.current": usage})
+		w.check(context.Background())
+	}
+	// a profile is captured each time usage crosses the threshold
+	assert.Len(t, recorder.Ended(), 2)
+}
@@ -0,0 +1,73 @@
+package scout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// workers runs the SDK's periodic background tasks, such as heartbeats, for as long as Scout is running.
+var workers struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// cleanups run once the tasks have returned
+	cleanups []func()
+}
+
+// startWorkers prepares for periodic tasks to be started. It is called when Scout starts.
+func startWorkers() {
+	workers.mu.Lock()
+	defer workers.mu.Unlock()
+	workers.ctx, workers.cancel = context.WithCancel(context.Background())
+}
+
+// stopWorkers cancels the periodic tasks and waits for them to return. It is called when Scout shuts down.
+func stopWorkers() {
+	workers.mu.Lock()
+	cancel := workers.cancel
+	cleanups := workers.cleanups
+	workers.cancel = nil
+	workers.cleanups = nil
+	workers.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	workers.wg.Wait()
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+}
+
+// onStopWorkers registers fn to be called when the workers are stopped.
+func onStopWorkers(fn func()) {
+	workers.mu.Lock()
+	defer workers.mu.Unlock()
+	workers.cleanups = append(workers.cleanups, fn)
+}
+
+// startPeriodic calls fn immediately and then every interval until the workers are stopped.
+func startPeriodic(interval time.Duration, fn func(ctx context.Context)) {
+	workers.mu.Lock()
+	defer workers.mu.Unlock()
+	if workers.cancel == nil {
+		return
+	}
+	ctx := workers.ctx
+	workers.wg.Add(1)
+	go func() {
+		defer workers.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fn(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,79 @@
+package scout
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace runs fn in a new span, recording the returned error on the span.
+// fn receives the span's context so nested spans are children of it.
+//
+// For example:
+//
+//	err := scout.Trace(ctx, "import.batch", func(ctx context.Context) error {
+//		return importBatch(ctx, batch)
+//	})
+func Trace(ctx context.Context, name string, fn func(ctx context.Context) error, tags ...attribute.KeyValue) error {
+	_, err := TraceValue(ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, tags...)
+	return err
+}
+
+// TraceValue is Trace for functions returning a value alongside an error.
+func TraceValue[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error), tags ...attribute.KeyValue) (value T, err error) {
+	_, ctx, finish := StartSpan(ctx, name, tags...)
+	defer finish(&err)
+	return fn(ctx)
+}
+
+// StartSpan starts a span and returns a function ending it. The function records the error
+// err points to, if any, and sets the span status, so it can be deferred with a named error result.
+//
+// For example:
+//
+//	func process(ctx context.Context) (err error) {
+//		_, ctx, finish := scout.StartSpan(ctx, "process")
+//		defer finish(&err)
+//		// some code
+//	}
+func StartSpan(ctx context.Context, name string, tags ...attribute.KeyValue) (trace.Span, context.Context, func(err *error)) {
+	span, ctx := StartTrace(ctx, name, tags...)
+	return span, ctx, finisher(span)
+}
+
+func finisher(span trace.Span) func(err *error) {
+	return func(err *error) {
+		if err != nil && *err != nil {
+			RecordSpanError(span, *err)
+			SetSpanErrorStatus(span, *err)
+		}
+		EndTrace(span)
+	}
+}
+
+// SuccessAttribute is set by Measure on its metric to tell apart successful and failed runs.
+const SuccessAttribute = "success"
+
+// Measure runs fn in a new span like Trace, and also records how long fn took as a metric (in seconds)
+// tagged with whether it succeeded. The metric is named after the span, so name must be a valid metric name,
+// see RecordMetric.
+//
+// For example:
+//
+//	err := scout.Measure(ctx, "import.batch", func(ctx context.Context) error {
+//		return importBatch(ctx, batch)
+//	})
+func Measure(ctx context.Context, name string, fn func(ctx context.Context) error, tags ...attribute.KeyValue) (err error) {
+	start := time.Now()
+	_, spanCtx, finish := StartSpan(ctx, name, tags...)
+	defer func() {
+		finish(&err)
+		metricTags := append(append(make([]attribute.KeyValue, 0, len(tags)+1), tags...), attribute.Bool(SuccessAttribute, err == nil))
+		RecordMetric(ctx, name, time.Since(start).Seconds(), metricTags...)
+	}()
+	return fn(spanCtx)
+}
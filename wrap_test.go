@@ -0,0 +1,124 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// recordSpans routes spans started by scout to a recorder for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	prev := loadTracer()
+	storeTracer(newTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	t.Cleanup(func() { storeTracer(prev) })
+	return recorder
+}
+
+func TestTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	err := Trace(context.Background(), "fails", func(ctx context.Context) error {
+		return errors.New("failed")
+	})
+	require.EqualError(t, err, "failed")
+
+	value, err := TraceValue(context.Background(), "succeeds", func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "fails", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "succeeds", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestRecordErrorOnSpan(t *testing.T) {
+	recorder := recordSpans(t)
+
+	span, ctx := StartTrace(context.Background(), "handler")
+	RecordErrorOnSpan(ctx, errors.New("failed"))
+	EndTrace(span)
+	RecordErrorOnSpan(context.Background(), errors.New("no span"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "handler", spans[0].Name())
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "scout-ctx", spans[1].Name())
+}
+
+func TestRecordErrors(t *testing.T) {
+	recorder := recordSpans(t)
+
+	errs := []error{errors.New("timeout"), nil, errors.New("invalid row"), errors.New("timeout")}
+	RecordErrors(context.Background(), errs)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Contains(t, events[0].Attributes, attribute.Int(ErrorCountAttribute, 2))
+	assert.Contains(t, events[0].Attributes, semconv.ExceptionMessage("timeout"))
+	assert.Contains(t, events[1].Attributes, attribute.Int(ErrorCountAttribute, 1))
+	assert.Contains(t, events[1].Attributes, semconv.ExceptionMessage("invalid row"))
+}
+
+func TestMeasure(t *testing.T) {
+	useConfig(t, &config{})
+	recorder := recordSpans(t)
+
+	tags := []attribute.KeyValue{attribute.String("source", "s3")}
+	require.NoError(t, Measure(context.Background(), "import.batch", func(ctx context.Context) error { return nil }, tags...))
+	require.EqualError(t, Measure(context.Background(), "import.batch", func(ctx context.Context) error {
+		return errors.New("failed")
+	}, tags...), "failed")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	for i, success := range []bool{true, false} {
+		span, metric := spans[2*i], spans[2*i+1]
+		assert.Equal(t, "import.batch", span.Name())
+		assert.Contains(t, span.Attributes(), attribute.String("source", "s3"))
+		assert.Equal(t, "scout-metric", metric.Name())
+		assert.Contains(t, metric.Attributes(), attribute.Bool(SuccessAttribute, success))
+		assert.Contains(t, metric.Attributes(), attribute.String("source", "s3"))
+		require.Len(t, metric.Events(), 1)
+		assert.Contains(t, metric.Events()[0].Attributes, attribute.String(MetricEventName, "import.batch"))
+	}
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Len(t, tags, 1, "the caller's tags are left unchanged")
+}
+
+func TestAddAttributesAndEvent(t *testing.T) {
+	recorder := recordSpans(t)
+
+	span, ctx := StartTrace(context.Background(), "handler")
+	AddAttributes(ctx, attribute.String("tenant", "acme"))
+	AddEvent(ctx, "cache.miss", attribute.String("cache.key", "user:42"))
+	EndTrace(span)
+	// without an active span, nothing is recorded
+	AddAttributes(context.Background(), attribute.String("tenant", "acme"))
+	AddEvent(context.Background(), "cache.miss")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("tenant", "acme"))
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "cache.miss", spans[0].Events()[0].Name)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("cache.key", "user:42")}, spans[0].Events()[0].Attributes)
+}